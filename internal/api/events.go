@@ -0,0 +1,175 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+const (
+	eventPollInterval = 500 * time.Millisecond
+	eventKeepAlive    = 15 * time.Second
+	eventBufferSize   = 64
+)
+
+// eventHub watches the database for changes from any process and fans
+// task events out to connected /events subscribers
+type eventHub struct {
+	db          *sql.DB
+	mu          sync.Mutex
+	subscribers map[chan service.TaskEvent]struct{}
+}
+
+func newEventHub(db *sql.DB) *eventHub {
+	return &eventHub{
+		db:          db,
+		subscribers: make(map[chan service.TaskEvent]struct{}),
+	}
+}
+
+// run polls PRAGMA data_version and broadcasts the diff between task
+// snapshots whenever it changes. Returns when ctx is cancelled.
+func (h *eventHub) run(ctx context.Context) error {
+	detector, err := storage.NewChangeDetector(h.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = detector.Close() // Best effort close
+	}()
+
+	snapshot, err := h.snapshot()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		changed, err := detector.Changed()
+		if err != nil {
+			log.Printf("Warning: Failed to check for changes: %v", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		next, err := h.snapshot()
+		if err != nil {
+			log.Printf("Warning: Failed to load tasks: %v", err)
+			continue
+		}
+		for _, event := range service.DiffTasks(snapshot, next) {
+			h.broadcast(event)
+		}
+		snapshot = next
+	}
+}
+
+// snapshot loads every non-deleted task, archived or not
+func (h *eventHub) snapshot() (map[string]*models.Task, error) {
+	tasks, err := storage.ListTasks(h.db, map[string]interface{}{
+		"include_archived": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service.SnapshotTasks(tasks), nil
+}
+
+func (h *eventHub) subscribe() chan service.TaskEvent {
+	ch := make(chan service.TaskEvent, eventBufferSize)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan service.TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// broadcast delivers an event to every subscriber. Subscribers whose buffer
+// is full are disconnected rather than blocking the hub.
+func (h *eventHub) broadcast(event service.TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// handleEvents handles GET /events as a Server-Sent Events stream.
+// Each event is named after its type (created, updated, moved, deleted)
+// and carries the TaskEvent as JSON data.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := s.events.subscribe()
+	defer s.events.unsubscribe(events)
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				// Disconnected for falling behind; client should reconnect
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Warning: Failed to encode event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+func TestHandleEvents(t *testing.T) {
+	s, db := newTestServer(t, "s3cret")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := s.events.run(ctx); err != nil {
+			t.Errorf("Change feed failed: %v", err)
+		}
+	}()
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", srv.URL+"/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// Write once the stream is subscribed, like the CLI would
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.events.mu.Lock()
+		subscribed := len(s.events.subscribers) > 0
+		s.events.mu.Unlock()
+		if subscribed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Stream never subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	task := mustCreate(t, db, &models.Task{Title: "Streamed", Priority: 3, Column: models.ColumnInbox})
+
+	type result struct {
+		name string
+		data string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		var r result
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				r.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				r.data = strings.TrimPrefix(line, "data: ")
+			case line == "" && r.data != "":
+				results <- r
+				return
+			}
+		}
+		r.err = scanner.Err()
+		results <- r
+	}()
+
+	select {
+	case r := <-results:
+		if r.err != nil {
+			t.Fatalf("Failed to read the stream: %v", r.err)
+		}
+		var event service.TaskEvent
+		if err := json.Unmarshal([]byte(r.data), &event); err != nil {
+			t.Fatalf("Failed to decode event %q: %v", r.data, err)
+		}
+		if r.name != "created" || event.Type != "created" || event.Task == nil || event.Task.ID != task.ID {
+			t.Errorf("Expected a created event for %s, got %s: %s", task.ID, r.name, r.data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No event received")
+	}
+}
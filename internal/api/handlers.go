@@ -0,0 +1,299 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// createTaskRequest is the body accepted by POST /tasks
+type createTaskRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Priority    *int     `json:"priority"` // Defaults to 3
+	Column      string   `json:"column"`   // Defaults to inbox
+	Progress    int      `json:"progress"`
+	ParentID    *string  `json:"parent_id"`
+	Tags        []string `json:"tags"`
+	DueAt       string   `json:"due_at"` // RFC3339 or anything 'ontop add -due' accepts
+	Board       string   `json:"board"`  // Board name or ID; defaults to the default board
+}
+
+// updateTaskRequest is the body accepted by PATCH /tasks/{id}.
+// Only fields present in the body are changed.
+type updateTaskRequest struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Priority    *int      `json:"priority"`
+	Progress    *int      `json:"progress"`
+	ParentID    *string   `json:"parent_id"` // Empty string removes the parent
+	Tags        *[]string `json:"tags"`
+	DueAt       *string   `json:"due_at"`  // Empty string removes the due date
+	Version     *int      `json:"version"` // If set, fail with 409 unless it matches
+}
+
+// moveTaskRequest is the body accepted by POST /tasks/{id}/move
+type moveTaskRequest struct {
+	Column string `json:"column"`
+}
+
+// handleListTasks handles GET /tasks?column=&priority=&tag=&archived=&board=
+func (s *Server) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := map[string]interface{}{
+		"archived": query.Get("archived") == "true",
+	}
+
+	if ref := query.Get("board"); ref != "" {
+		board, err := s.boards.Get(ref)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		filters["board_id"] = board.ID
+	}
+
+	if column := query.Get("column"); column != "" {
+		if !models.IsValidColumn(column) {
+			writeError(w, http.StatusBadRequest, invalidColumnError(column))
+			return
+		}
+		filters["column"] = column
+	}
+
+	if priorityStr := query.Get("priority"); priorityStr != "" {
+		priority, err := strconv.Atoi(priorityStr)
+		if err != nil || priority < 1 || priority > 5 {
+			writeError(w, http.StatusBadRequest, errors.New("priority must be between 1 and 5"))
+			return
+		}
+		filters["priority"] = priority
+	}
+
+	if tag := query.Get("tag"); tag != "" {
+		filters["tag"] = tag
+	}
+
+	tasks, err := storage.ListTasks(s.db, filters)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tasks == nil {
+		tasks = []*models.Task{}
+	}
+	writeJSON(w, http.StatusOK, tasks)
+}
+
+// handleListBoards handles GET /boards
+func (s *Server) handleListBoards(w http.ResponseWriter, r *http.Request) {
+	boards, err := s.boards.List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, boards)
+}
+
+// handleGetTask handles GET /tasks/{id}
+func (s *Server) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookupTask(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// handleCreateTask handles POST /tasks
+func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	var req createTaskRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	priority := 3
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	task := &models.Task{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    priority,
+		Column:      req.Column,
+		Progress:    req.Progress,
+		ParentID:    req.ParentID,
+		Tags:        req.Tags,
+	}
+	if req.DueAt != "" {
+		dueAt, err := parseDueAt(req.DueAt)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		task.DueAt = &dueAt
+	}
+	if req.Board != "" {
+		board, err := s.boards.Get(req.Board)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		task.BoardID = board.ID
+	}
+	if err := s.tasks.Create(task); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, task)
+}
+
+// handleUpdateTask handles PATCH /tasks/{id}
+func (s *Server) handleUpdateTask(w http.ResponseWriter, r *http.Request) {
+	var req updateTaskRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var dueAt *time.Time
+	if req.DueAt != nil {
+		dueAt = &time.Time{} // The zero time removes the due date
+		if *req.DueAt != "" {
+			parsed, err := parseDueAt(*req.DueAt)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			dueAt = &parsed
+		}
+	}
+
+	task, err := s.tasks.Update(r.PathValue("id"), service.TaskPatch{
+		Title:       req.Title,
+		Description: req.Description,
+		Priority:    req.Priority,
+		Progress:    req.Progress,
+		ParentID:    req.ParentID,
+		Tags:        req.Tags,
+		DueAt:       dueAt,
+		Version:     req.Version,
+	})
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// handleMoveTask handles POST /tasks/{id}/move
+func (s *Server) handleMoveTask(w http.ResponseWriter, r *http.Request) {
+	var req moveTaskRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	task, err := s.tasks.Move(r.PathValue("id"), req.Column)
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, task)
+}
+
+// handleArchiveTask handles POST /tasks/{id}/archive and /unarchive
+func (s *Server) handleArchiveTask(archived bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		task, err := s.tasks.Archive(r.PathValue("id"), archived)
+		if err != nil {
+			s.writeServiceError(w, r, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, task)
+	}
+}
+
+// handleDeleteTask handles DELETE /tasks/{id}
+func (s *Server) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	if err := s.tasks.Delete(r.PathValue("id")); err != nil {
+		s.writeServiceError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListSubtasks handles GET /tasks/{id}/subtasks
+func (s *Server) handleListSubtasks(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.lookupTask(w, r)
+	if !ok {
+		return
+	}
+
+	allTasks, err := storage.ListTasks(s.db, map[string]interface{}{
+		"archived": r.URL.Query().Get("archived") == "true",
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	subtasks := service.GetSubtasksForParent(allTasks, task.ID)
+	if subtasks == nil {
+		subtasks = []*models.Task{}
+	}
+	writeJSON(w, http.StatusOK, subtasks)
+}
+
+// Helper functions
+
+// lookupTask loads the task named by the {id} path value, writing an error
+// response on failure
+func (s *Server) lookupTask(w http.ResponseWriter, r *http.Request) (*models.Task, bool) {
+	task, err := s.tasks.Get(r.PathValue("id"))
+	if err != nil {
+		s.writeServiceError(w, r, err)
+		return nil, false
+	}
+	return task, true
+}
+
+// writeServiceError maps TaskService errors to HTTP status codes. Conflicts
+// include the current stored task so clients can merge and retry.
+func (s *Server) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrTaskNotFound), errors.Is(err, service.ErrBoardNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, storage.ErrConflict):
+		body := map[string]interface{}{"error": err.Error()}
+		if current, getErr := s.tasks.Get(r.PathValue("id")); getErr == nil {
+			body["current"] = current
+		}
+		writeJSON(w, http.StatusConflict, body)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func invalidColumnError(column string) error {
+	return fmt.Errorf("invalid column '%s'. Valid columns: %s", column, strings.Join(models.ValidColumns(), ", "))
+}
+
+// parseDueAt accepts an RFC3339 timestamp or a due date like 'ontop add -due'
+func parseDueAt(value string) (time.Time, error) {
+	if dueAt, err := time.Parse(time.RFC3339, value); err == nil {
+		return dueAt, nil
+	}
+	return service.ParseDueDate(value, time.Now())
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+func TestHandleListTasks(t *testing.T) {
+	s, db := newTestServer(t, "")
+	work, err := service.NewBoardService(db).Create("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	urgent := mustCreate(t, db, &models.Task{Title: "Urgent", Priority: 1, Column: models.ColumnInbox, Tags: []string{"bug"}})
+	doing := mustCreate(t, db, &models.Task{Title: "Doing", Priority: 3, Column: models.ColumnInProgress})
+	old := mustCreate(t, db, &models.Task{Title: "Old", Priority: 3, Column: models.ColumnDone})
+	if _, err := service.NewTaskService(db).Archive(old.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	other := mustCreate(t, db, &models.Task{Title: "Other board", Priority: 3, Column: models.ColumnInbox, BoardID: work.ID})
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string // Task IDs, in any order
+		wantErr int      // Status of an invalid query; 0 if valid
+	}{
+		{name: "active tasks of every board", query: "", want: []string{urgent.ID, doing.ID, other.ID}},
+		{name: "column", query: "?column=in_progress", want: []string{doing.ID}},
+		{name: "priority", query: "?priority=1", want: []string{urgent.ID}},
+		{name: "tag", query: "?tag=bug", want: []string{urgent.ID}},
+		{name: "archived", query: "?archived=true", want: []string{old.ID}},
+		{name: "board", query: "?board=work", want: []string{other.ID}},
+		{name: "no matches", query: "?column=done", want: []string{}},
+		{name: "invalid column", query: "?column=later", wantErr: http.StatusBadRequest},
+		{name: "invalid priority", query: "?priority=9", wantErr: http.StatusBadRequest},
+		{name: "unknown board", query: "?board=home", wantErr: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, s, "GET", "/tasks"+tt.query, nil)
+			if tt.wantErr != 0 {
+				if rec.Code != tt.wantErr {
+					t.Errorf("Expected status %d, got %d: %s", tt.wantErr, rec.Code, rec.Body.String())
+				}
+				return
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var got []string
+			for _, task := range decode[[]*models.Task](t, rec) {
+				got = append(got, task.ID)
+			}
+			slices.Sort(got)
+			want := slices.Sorted(slices.Values(tt.want))
+			if !slices.Equal(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestHandleCreateTask(t *testing.T) {
+	s, db := newTestServer(t, "")
+	parent := mustCreate(t, db, &models.Task{Title: "Parent", Priority: 3, Column: models.ColumnInbox})
+
+	tests := []struct {
+		name    string
+		body    interface{}
+		want    int
+		wantErr string // Part of the error message
+	}{
+		{name: "defaults", body: map[string]interface{}{"title": "New"}, want: http.StatusCreated},
+		{name: "subtask", body: map[string]interface{}{"title": "Sub", "parent_id": parent.ID, "due_at": "2030-01-02T15:04:05Z"}, want: http.StatusCreated},
+		{name: "missing title", body: map[string]interface{}{"priority": 2}, want: http.StatusBadRequest, wantErr: "title"},
+		{name: "invalid priority", body: map[string]interface{}{"title": "New", "priority": 9}, want: http.StatusBadRequest, wantErr: "priority"},
+		{name: "invalid column", body: map[string]interface{}{"title": "New", "column": "later"}, want: http.StatusBadRequest, wantErr: "column"},
+		{name: "invalid due date", body: map[string]interface{}{"title": "New", "due_at": "someday"}, want: http.StatusBadRequest},
+		{name: "unknown field", body: map[string]interface{}{"title": "New", "colour": "red"}, want: http.StatusBadRequest, wantErr: "colour"},
+		{name: "invalid JSON", body: "{", want: http.StatusBadRequest, wantErr: "invalid JSON"},
+		{name: "missing parent", body: map[string]interface{}{"title": "New", "parent_id": "nope"}, want: http.StatusBadRequest, wantErr: "parent task not found"},
+		{name: "unknown board", body: map[string]interface{}{"title": "New", "board": "home"}, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, s, "POST", "/tasks", tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want != http.StatusCreated {
+				if body := decode[map[string]string](t, rec); !strings.Contains(body["error"], tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %q", tt.wantErr, body["error"])
+				}
+				return
+			}
+			task := decode[*models.Task](t, rec)
+			if task.ID == "" || task.Priority != 3 || task.Column != models.ColumnInbox || task.BoardID != models.DefaultBoardID {
+				t.Errorf("Expected a task with the defaults, got %+v", task)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateTask(t *testing.T) {
+	s, db := newTestServer(t, "")
+	task := mustCreate(t, db, &models.Task{Title: "Task", Priority: 3, Column: models.ColumnInbox, Tags: []string{"a"}})
+
+	rec := do(t, s, "PATCH", "/tasks/"+task.ID, map[string]interface{}{"title": "Renamed", "version": task.Version})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	updated := decode[*models.Task](t, rec)
+	if updated.Title != "Renamed" || updated.Priority != 3 || !slices.Equal(updated.Tags, []string{"a"}) {
+		t.Errorf("Expected only the title to change, got %+v", updated)
+	}
+	if updated.Version != task.Version+1 {
+		t.Errorf("Expected version %d, got %d", task.Version+1, updated.Version)
+	}
+
+	// A stale version is a conflict, answered with the stored task
+	rec = do(t, s, "PATCH", "/tasks/"+task.ID, map[string]interface{}{"title": "Stale", "version": task.Version})
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Expected status 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	conflict := decode[struct {
+		Error   string       `json:"error"`
+		Current *models.Task `json:"current"`
+	}](t, rec)
+	if conflict.Current == nil || conflict.Current.Title != "Renamed" || conflict.Current.Version != updated.Version {
+		t.Errorf("Expected the current task in the conflict, got %+v", conflict.Current)
+	}
+
+	tests := []struct {
+		name string
+		body interface{}
+		want int
+	}{
+		{name: "invalid priority", body: map[string]interface{}{"priority": 0}, want: http.StatusBadRequest},
+		{name: "invalid progress", body: map[string]interface{}{"progress": 101}, want: http.StatusBadRequest},
+		{name: "empty title", body: map[string]interface{}{"title": ""}, want: http.StatusBadRequest},
+		{name: "own parent", body: map[string]interface{}{"parent_id": task.ID}, want: http.StatusBadRequest},
+		{name: "unknown field", body: map[string]interface{}{"column": "done"}, want: http.StatusBadRequest},
+		{name: "remove the due date", body: map[string]interface{}{"due_at": ""}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, s, "PATCH", "/tasks/"+task.ID, tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlers_NotFound(t *testing.T) {
+	s, _ := newTestServer(t, "")
+
+	tests := []struct {
+		method, path string
+		body         interface{}
+	}{
+		{"GET", "/tasks/nope", nil},
+		{"PATCH", "/tasks/nope", map[string]string{"title": "New"}},
+		{"DELETE", "/tasks/nope", nil},
+		{"POST", "/tasks/nope/move", map[string]string{"column": "done"}},
+		{"POST", "/tasks/nope/archive", nil},
+		{"POST", "/tasks/nope/unarchive", nil},
+		{"GET", "/tasks/nope/subtasks", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := do(t, s, tt.method, tt.path, tt.body)
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("Expected status 404, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleMoveAndDeleteTask(t *testing.T) {
+	s, db := newTestServer(t, "")
+	task := mustCreate(t, db, &models.Task{Title: "Task", Priority: 3, Column: models.ColumnInbox})
+	mustCreate(t, db, &models.Task{Title: "Sub", Priority: 3, Column: models.ColumnInbox, ParentID: &task.ID})
+
+	rec := do(t, s, "POST", "/tasks/"+task.ID+"/move", map[string]string{"column": "later"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid column, got %d", rec.Code)
+	}
+	rec = do(t, s, "POST", "/tasks/"+task.ID+"/move", map[string]string{"column": models.ColumnDone})
+	if rec.Code != http.StatusOK || decode[*models.Task](t, rec).Column != models.ColumnDone {
+		t.Errorf("Expected the task moved to done, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = do(t, s, "GET", "/tasks/"+task.ID+"/subtasks", nil)
+	if rec.Code != http.StatusOK || len(decode[[]*models.Task](t, rec)) != 1 {
+		t.Errorf("Expected one subtask, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec = do(t, s, "DELETE", "/tasks/"+task.ID, nil); rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec = do(t, s, "GET", "/tasks/"+task.ID, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected the deleted task to be gone, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// Server exposes tasks over a local JSON HTTP API
+type Server struct {
+	db     *sql.DB
+	token  string // Optional bearer token; empty disables auth
+	mux    *http.ServeMux
+	events *eventHub
+	tasks  *service.TaskService
+	boards *service.BoardService
+}
+
+// NewServer creates a new API server backed by the given database.
+// If token is non-empty, every request must send "Authorization: Bearer <token>".
+func NewServer(db *sql.DB, token string) *Server {
+	s := &Server{
+		db:     db,
+		token:  token,
+		mux:    http.NewServeMux(),
+		events: newEventHub(db),
+		tasks:  service.NewTaskService(db),
+		boards: service.NewBoardService(db),
+	}
+	s.routes()
+	return s
+}
+
+// routes registers all API endpoints
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /tasks", s.handleListTasks)
+	s.mux.HandleFunc("POST /tasks", s.handleCreateTask)
+	s.mux.HandleFunc("GET /tasks/{id}", s.handleGetTask)
+	s.mux.HandleFunc("PATCH /tasks/{id}", s.handleUpdateTask)
+	s.mux.HandleFunc("DELETE /tasks/{id}", s.handleDeleteTask)
+	s.mux.HandleFunc("POST /tasks/{id}/move", s.handleMoveTask)
+	s.mux.HandleFunc("POST /tasks/{id}/archive", s.handleArchiveTask(true))
+	s.mux.HandleFunc("POST /tasks/{id}/unarchive", s.handleArchiveTask(false))
+	s.mux.HandleFunc("GET /tasks/{id}/subtasks", s.handleListSubtasks)
+	s.mux.HandleFunc("GET /boards", s.handleListBoards)
+	s.mux.HandleFunc("GET /events", s.handleEvents)
+}
+
+// ServeHTTP implements http.Handler with bearer token authentication
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="ontop"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe starts the change feed and serves on addr until the server fails
+func (s *Server) ListenAndServe(addr string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- s.events.run(ctx)
+	}()
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case err := <-watchErr:
+		_ = srv.Close() // Best effort close
+		if err == nil {
+			err = errors.New("change feed stopped unexpectedly")
+		}
+		return fmt.Errorf("change feed failed: %w", err)
+	}
+}
+
+// authorized checks the request's bearer token in constant time
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// Helper functions
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: Failed to encode response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errors.New("invalid JSON body: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// Helper to create a server backed by a fresh database
+func newTestServer(t *testing.T, token string) (*Server, *sql.DB) {
+	t.Helper()
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := storage.InitSchema(db); err != nil {
+		t.Fatalf("Failed to init schema: %v", err)
+	}
+	return NewServer(db, token), db
+}
+
+// Helper to create a task directly in the database
+func mustCreate(t *testing.T, db *sql.DB, task *models.Task) *models.Task {
+	t.Helper()
+	if err := service.NewTaskService(db).Create(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	return task
+}
+
+// Helper to send a request to the server, with body encoded as JSON unless
+// it is a string. Headers are given as name, value pairs.
+func do(t *testing.T, s *Server, method, path string, body interface{}, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		data = []byte(b)
+	default:
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+// Helper to decode a JSON response body
+func decode[T any](t *testing.T, rec *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("Failed to decode %q: %v", rec.Body.String(), err)
+	}
+	return v
+}
+
+func TestServer_Auth(t *testing.T) {
+	s, _ := newTestServer(t, "s3cret")
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"not a bearer token", "Basic s3cret", http.StatusUnauthorized},
+		{"right token", "Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var headers []string
+			if tt.header != "" {
+				headers = []string{"Authorization", tt.header}
+			}
+			rec := do(t, s, "GET", "/tasks", nil, headers...)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestServer_NoAuth(t *testing.T) {
+	s, _ := newTestServer(t, "")
+	if rec := do(t, s, "GET", "/tasks", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 without a token configured, got %d", rec.Code)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"cmp"
 	"database/sql"
 	"encoding/json"
 	"flag"
@@ -9,9 +10,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lucasefe/ontop/internal/config"
 	"github.com/lucasefe/ontop/internal/models"
 	"github.com/lucasefe/ontop/internal/service"
-	"github.com/lucasefe/ontop/internal/storage"
 )
 
 // AddCommand implements the 'ontop add' command
@@ -21,116 +22,243 @@ func AddCommand(db *sql.DB, args []string) {
 	description := fs.String("description", "", "Full task description (optional)")
 	priority := fs.Int("priority", 3, "Task priority (1-5, where 1 is highest)")
 	tagsStr := fs.String("tags", "", "Comma-separated list of tags")
-	parentID := fs.String("parent", "", "Parent task ID for subtasks")
+	parentID := fs.String("parent", "", "Parent task for subtasks (ID, ID prefix, #N, @last or @selected)")
 	column := fs.String("column", models.ColumnInbox, "Column to place task in (inbox, in_progress, done)")
 	progress := fs.Int("progress", 0, "Initial progress percentage (0-100)")
+	due := fs.String("due", "", "Due date (today, tomorrow, a weekday, 3d, 2w or YYYY-MM-DD)")
+	templateName := fs.String("template", "", "Create the task and its subtasks from a template")
+	vars := make(templateVars)
+	fs.Var(vars, "var", "Template variable as name=value (repeatable)")
 	jsonOutput := fs.Bool("json", false, "Output result as JSON")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Usage: ontop add [options]
+		fmt.Fprintf(os.Stderr, `Usage: ontop add [options] ["title with inline tokens"]
 
 Create a new task with the specified title and optional description.
 
+The title can be given as text instead of -title, with inline tokens that
+set other fields:
+
+    #tag          Add a tag
+    !1 .. !5      Set the priority
+    ^task         Make it a subtask of task (ID, ID prefix, #N, @last or @selected)
+    due:when      Set the due date (today, tomorrow, fri, 3d, 2w or YYYY-MM-DD)
+    @column       Place it in a column (inbox, in_progress, done)
+
+Prefix a word with a backslash to keep it in the title as-is (e.g. \#42).
+Options given as flags take precedence over inline tokens.
+
+With -template, the task and its subtasks come from a template (see 'ontop
+template'). A title, inline tokens and flags override the template's values
+for the parent task; tags are added to the template's.
+
 OPTIONS:
     -title string      Task title (short, required)
     -description string Full task description (optional)
     -priority int      Task priority (1-5, where 1 is highest) (default: 3)
     -tags string       Comma-separated list of tags
-    -parent string     Parent task ID for subtasks
+    -parent string     Parent task for subtasks (ID, ID prefix, #N, @last or @selected)
     -column string     Column to place task in: inbox, in_progress, done (default: inbox)
     -progress int      Initial progress percentage (0-100) (default: 0)
+    -due string        Due date (today, tomorrow, a weekday, 3d, 2w or YYYY-MM-DD)
+    -template string   Create the task and its subtasks from a template
+    -var name=value    Template variable (repeatable)
     -json              Output result as JSON
 
 EXAMPLES:
     ontop add -title "Implement user authentication" -description "Add OAuth2 support with Google and GitHub"
     ontop add -title "Fix login bug" -priority 1 -tags "bug,urgent"
     ontop add -title "Write tests" -parent 01K98X44S6TZC6EREHC2RK0JGJ
+    ontop add -title "Write docs" -parent @last
+    ontop add "Fix login bug #bug #urgent !1 due:fri @in_progress"
+    ontop add "Write docs ^@last due:tomorrow" -description "Cover the new flags"
+    ontop add -template release -var version=1.4
+    ontop add -template release -var version=1.5 "Hotfix {{version}} !1"
 `)
 	}
 
-	if err := fs.Parse(args); err != nil {
-		os.Exit(2)
+	// Flags may come before or after the quick-capture text
+	var words []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(2)
+		}
+		rest = fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		words = append(words, rest[0])
+		rest = rest[1:]
 	}
 
-	// Validate title
-	if *title == "" {
-		fmt.Fprintf(os.Stderr, "Error: Task title is required\n")
-		fs.Usage()
-		os.Exit(2)
-	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
 
-	// Validate inputs
-	if *priority < 1 || *priority > 5 {
-		fmt.Fprintf(os.Stderr, "Error: Priority must be between 1 and 5\n")
-		os.Exit(2)
-	}
+	// Parse inline tokens; explicit flags win over them
+	var tags []string
+	var dueAt *time.Time
+	parentRef := *parentID
+	if len(words) > 0 {
+		if set["title"] {
+			fmt.Fprintf(os.Stderr, "Error: Use either -title or quick-capture text, not both\n")
+			os.Exit(2)
+		}
+		qa, err := service.ParseQuickAdd(strings.Join(words, " "), time.Now())
+		if err != nil {
+			exitWithError("parse task", err)
+		}
+		if qa.ParentRef != "" && set["parent"] {
+			fmt.Fprintf(os.Stderr, "Error: Use either -parent or ^parent, not both\n")
+			os.Exit(2)
+		}
 
-	if !models.IsValidColumn(*column) {
-		fmt.Fprintf(os.Stderr, "Error: Invalid column '%s'. Valid columns: %s\n", *column, strings.Join(models.ValidColumns(), ", "))
-		os.Exit(2)
+		*title = qa.Title
+		tags = qa.Tags
+		dueAt = qa.DueAt
+		if qa.Priority > 0 && !set["priority"] {
+			*priority = qa.Priority
+			set["priority"] = true
+		}
+		if qa.Column != "" && !set["column"] {
+			*column = qa.Column
+			set["column"] = true
+		}
+		if qa.ParentRef != "" {
+			parentRef = qa.ParentRef
+		}
 	}
 
-	if *progress < 0 || *progress > 100 {
-		fmt.Fprintf(os.Stderr, "Error: Progress must be between 0 and 100\n")
+	// Validate title (templates bring their own)
+	if *title == "" && *templateName == "" {
+		fmt.Fprintf(os.Stderr, "Error: Task title is required\n")
+		fs.Usage()
 		os.Exit(2)
 	}
 
 	// Parse tags
-	var tags []string
 	if *tagsStr != "" {
-		tags = strings.Split(*tagsStr, ",")
-		for i, tag := range tags {
-			tags[i] = strings.TrimSpace(tag)
-		}
-	} else {
-		tags = []string{}
+		tags = append(tags, strings.Split(*tagsStr, ",")...)
 	}
 
-	// Validate parent exists if provided
-	var parentIDPtr *string
-	if *parentID != "" {
-		_, err := storage.GetTask(db, *parentID)
+	if *due != "" {
+		parsed, err := service.ParseDueDate(*due, time.Now())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Parent task '%s' not found: %v\n", *parentID, err)
-			os.Exit(2)
+			exitWithError("parse due date", err)
 		}
-		parentIDPtr = parentID
+		dueAt = &parsed
 	}
 
-	// Create task
-	now := time.Now()
+	var parentIDPtr *string
+	if parentRef != "" {
+		resolved := resolveTaskRef(db, parentRef)
+		parentIDPtr = &resolved
+	}
+
+	// Create task (validation and completion rules live in the service)
 	task := &models.Task{
-		ID:          service.GenerateID(),
 		Title:       *title,
 		Description: *description,
 		Priority:    *priority,
 		Column:      *column,
 		Progress:    *progress,
 		ParentID:    parentIDPtr,
-		Archived:    false,
+		BoardID:     currentBoard(db).ID,
 		Tags:        tags,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-		CompletedAt: nil,
-		DeletedAt:   nil,
+		DueAt:       dueAt,
 	}
 
-	if err := storage.CreateTask(db, task); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to create task: %v\n", err)
-		os.Exit(1)
+	svc := service.NewTaskService(db)
+	if *templateName != "" {
+		parent, subtasks := newTasksFromTemplate(*templateName, vars, task, set)
+		if err := svc.CreateWithSubtasks(parent, subtasks); err != nil {
+			exitWithError("create tasks", err)
+		}
+		rememberLastTask(db, parent.ID)
+		if *jsonOutput {
+			printJSON(map[string]interface{}{"task": parent, "subtasks": subtasks})
+		} else {
+			fmt.Printf("Created task %s: %s (with %d subtasks from template %s)\n", parent.ID, parent.Title, len(subtasks), *templateName)
+		}
+		os.Exit(0)
+	}
+	if len(vars) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: -var requires -template\n")
+		os.Exit(2)
 	}
 
+	if err := svc.Create(task); err != nil {
+		exitWithError("create task", err)
+	}
+	rememberLastTask(db, task.ID)
+
 	// Output result
 	if *jsonOutput {
-		output, err := json.MarshalIndent(task, "", "  ")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to marshal JSON: %v\n", err)
-			os.Exit(1)
-		}
-		fmt.Println(string(output))
+		printJSON(task)
 	} else {
-		fmt.Printf("Created task %s: %s\n", task.ID, task.Description)
+		fmt.Printf("Created task %s: %s\n", task.ID, task.Title)
 	}
 
 	os.Exit(0)
 }
+
+// templateVars collects repeated -var name=value flags
+type templateVars map[string]string
+
+func (v templateVars) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v templateVars) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got '%s'", value)
+	}
+	v[name] = val
+	return nil
+}
+
+// newTasksFromTemplate builds the tasks to create from a template. Fields of
+// overrides that were given on the command line replace the template's.
+func newTasksFromTemplate(name string, vars templateVars, overrides *models.Task, set map[string]bool) (*models.Task, []*models.Task) {
+	tmpl, err := service.LoadTemplate(config.GetTemplatesDir(), name)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+
+	// Variables also apply to a title given on the command line
+	tmpl.Title = cmp.Or(overrides.Title, tmpl.Title)
+	if set["description"] {
+		tmpl.Description = overrides.Description
+	}
+	parent, subtasks, err := tmpl.NewTasks(vars)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+
+	if set["priority"] {
+		parent.Priority = overrides.Priority
+	}
+	if set["progress"] {
+		parent.Progress = overrides.Progress
+	}
+	parent.Column = overrides.Column
+	parent.ParentID = overrides.ParentID
+	parent.BoardID = overrides.BoardID
+	parent.Tags = append(parent.Tags, overrides.Tags...)
+	parent.DueAt = overrides.DueAt
+	return parent, subtasks
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to marshal JSON: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(output))
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// ArchiveCommand implements the 'ontop archive' command
+func ArchiveCommand(db *sql.DB, args []string) {
+	setArchived(db, args, "archive", true)
+}
+
+// UnarchiveCommand implements the 'ontop unarchive' command (alias: restore)
+func UnarchiveCommand(db *sql.DB, args []string) {
+	setArchived(db, args, "unarchive", false)
+}
+
+func setArchived(db *sql.DB, args []string, name string, archived bool) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+
+	fs.Usage = func() {
+		if archived {
+			fmt.Fprintf(os.Stderr, `Usage: ontop archive <task>...
+
+Archive one or more tasks. Archived tasks are hidden from the board and from
+'ontop list' unless -archived is given.
+
+EXAMPLES:
+    ontop archive 20251104-143000-00001
+    ontop archive '#2' '#5'
+`)
+		} else {
+			fmt.Fprintf(os.Stderr, `Usage: ontop unarchive <task>...
+
+Restore one or more archived tasks. Also available as 'ontop restore'.
+
+EXAMPLES:
+    ontop unarchive 20251104-143000-00001
+    ontop restore @last
+`)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Error: Task ID is required\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// Resolve every reference before changing anything
+	taskIDs := make([]string, fs.NArg())
+	for i, ref := range fs.Args() {
+		taskIDs[i] = resolveTaskRef(db, ref)
+	}
+
+	svc := service.NewTaskService(db)
+	for _, taskID := range taskIDs {
+		if _, err := svc.Archive(taskID, archived); err != nil {
+			exitWithError(name+" task", err)
+		}
+		if archived {
+			fmt.Printf("Archived task %s\n", taskID)
+		} else {
+			fmt.Printf("Unarchived task %s\n", taskID)
+		}
+	}
+	rememberLastTask(db, taskIDs[len(taskIDs)-1])
+
+	os.Exit(0)
+}
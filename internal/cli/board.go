@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// boardUsage is the help text for 'ontop board'
+const boardUsage = `Usage: ontop board <subcommand> [arguments]
+
+Boards keep separate sets of tasks, like personal and work projects, in one
+database. Commands work on the current board: the one given with the global
+--board flag, or else the last one switched to (saved in the config file).
+Every database has a board named 'default'.
+
+SUBCOMMANDS:
+    list                     List boards with their number of active tasks
+    create <name>            Create a board
+    rename <board> <name>    Rename a board
+    switch <board>           Make a board the current one
+
+EXAMPLES:
+    ontop board create work
+    ontop board switch work
+    ontop --board default list
+    ontop board rename work "Acme Corp"
+`
+
+// BoardCommand implements the 'ontop board' command
+func BoardCommand(db *sql.DB, args []string) {
+	usage := func() {
+		fmt.Fprint(os.Stderr, boardUsage)
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	boards := service.NewBoardService(db)
+	switch args[0] {
+	case "list", "ls":
+		requireArgs(args, 1, "list")
+		listBoards(db, boards)
+
+	case "create":
+		requireArgs(args, 2, "create <name>")
+		board, err := boards.Create(args[1])
+		if err != nil {
+			exitWithError("create board", err)
+		}
+		fmt.Printf("Created board %s. Switch to it with 'ontop board switch %s'.\n", board.Name, board.Name)
+
+	case "rename":
+		requireArgs(args, 3, "rename <board> <name>")
+		old, err := boards.Get(args[1])
+		if err != nil {
+			exitWithError("rename board", err)
+		}
+		board, err := boards.Rename(old.ID, args[2])
+		if err != nil {
+			exitWithError("rename board", err)
+		}
+		// Keep the config pointing at the current board
+		err = config.Update(func(cfg *config.Config) {
+			if strings.EqualFold(cfg.Board, old.Name) {
+				cfg.Board = board.Name
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to update config: %v\n", err)
+		}
+		fmt.Printf("Renamed board %s to %s\n", old.Name, board.Name)
+
+	case "switch":
+		requireArgs(args, 2, "switch <board>")
+		board, err := boards.Get(args[1])
+		if err != nil {
+			exitWithError("switch board", err)
+		}
+		if err := saveCurrentBoard(board); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to save current board: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Switched to board %s\n", board.Name)
+		if _, sources, err := config.LoadSources(); err == nil && sources["board"] != config.SourceGlobal && sources["board"] != config.SourceDefault {
+			fmt.Fprintf(os.Stderr, "Warning: The board is also set by the %s config layer, which takes precedence\n", sources["board"])
+		}
+
+	case "-h", "-help", "--help", "help":
+		usage()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown subcommand '%s'\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(0)
+}
+
+func listBoards(db *sql.DB, boards *service.BoardService) {
+	list, err := boards.List()
+	if err != nil {
+		exitWithError("list boards", err)
+	}
+	counts, err := storage.CountTasksByBoard(db)
+	if err != nil {
+		exitWithError("list boards", err)
+	}
+
+	current := currentBoard(db)
+	for _, board := range list {
+		marker := "  "
+		if board.ID == current.ID {
+			marker = "* "
+		}
+		fmt.Printf("%s%-20s %d tasks\n", marker, board.Name, counts[board.ID])
+	}
+}
+
+// requireArgs exits with a usage error unless args has exactly n entries
+func requireArgs(args []string, n int, usage string) {
+	if len(args) != n {
+		fmt.Fprintf(os.Stderr, "Error: Usage: ontop board %s\n", usage)
+		os.Exit(2)
+	}
+}
+
+// currentBoard returns the board commands work on, exiting if it does not
+// exist
+func currentBoard(db *sql.DB) *models.Board {
+	cfg, err := config.Load()
+	if err != nil {
+		printConfigError("Warning", err) // The valid settings still apply
+	}
+	board, err := service.NewBoardService(db).Get(cfg.Board)
+	if err != nil {
+		exitWithError("find board", err)
+	}
+	return board
+}
+
+// saveCurrentBoard makes board the current board in the config file
+func saveCurrentBoard(board *models.Board) error {
+	return config.Update(func(cfg *config.Config) {
+		cfg.Board = board.Name
+		if board.ID == models.DefaultBoardID {
+			cfg.Board = ""
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// bulkUsage is the help text for 'ontop bulk'
+const bulkUsage = `Usage: ontop bulk <action> [arguments] [options]
+
+Apply one change to many tasks at once. Tasks are selected with the same
+filters as 'ontop list', or read from stdin with -stdin (one task per line;
+the first word is used, so 'ontop list' output can be piped in). The matching
+tasks are always previewed; nothing changes until you confirm. All changes
+are applied in a single transaction: if one fails, none are applied.
+
+ACTIONS:
+    move <column>           Move tasks to a column (inbox, in_progress, done)
+    tag add <tags>          Add comma-separated tags
+    tag remove <tags>       Remove comma-separated tags
+    priority <1-5>          Set priority
+    archive                 Archive tasks
+    delete                  Delete tasks and their subtasks
+
+OPTIONS:
+%s    -stdin                     Read task IDs, ID prefixes or #N aliases from stdin
+    -dry-run                   Only show which tasks would change
+    -yes                       Apply without asking for confirmation
+
+EXAMPLES:
+    ontop bulk archive -column done -completed-before 2025-10-01
+    ontop bulk tag add q3 -priority 1
+    ontop bulk move in_progress -tag urgent -dry-run
+    ontop list -tag old | ontop bulk delete -stdin -yes
+`
+
+// bulkAction is a parsed 'ontop bulk' action
+type bulkAction struct {
+	description string // Preview text, e.g. "move to Done"
+	summary     string // Result format with a %d task count
+	patch       service.TaskPatch
+	delete      bool
+}
+
+// BulkCommand implements the 'ontop bulk' command
+func BulkCommand(db *sql.DB, args []string) {
+	fs := flag.NewFlagSet("bulk", flag.ExitOnError)
+	filters := addFilterFlags(fs)
+	fromStdin := fs.Bool("stdin", false, "Read task IDs from stdin")
+	dryRun := fs.Bool("dry-run", false, "Only show which tasks would change")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, bulkUsage, filterUsage)
+	}
+
+	// Action and its arguments come before the options
+	action, rest := parseBulkAction(args, fs.Usage)
+	if err := fs.Parse(rest); err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: Unexpected argument '%s'\n", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// Select tasks
+	var tasks []*models.Task
+	if *fromStdin {
+		if filters.isSet() {
+			fmt.Fprintf(os.Stderr, "Error: -stdin cannot be combined with filters\n")
+			os.Exit(2)
+		}
+		if !*yes && !*dryRun {
+			fmt.Fprintf(os.Stderr, "Error: Stdin is used for task IDs, so pass -yes to confirm (or -dry-run to preview)\n")
+			os.Exit(2)
+		}
+		tasks = readTasks(db, os.Stdin)
+	} else {
+		if !filters.isSet() {
+			fmt.Fprintf(os.Stderr, "Error: Select tasks with at least one filter, or use -stdin\n")
+			os.Exit(2)
+		}
+		tasks = filters.listTasks(db)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found.")
+		os.Exit(0)
+	}
+
+	// Preview
+	fmt.Printf("Will %s %d task(s):\n\n", action.description, len(tasks))
+	for _, task := range tasks {
+		fmt.Printf("  [%s] P%d | %s | %s\n", task.ID, task.Priority, formatColumnDisplay(task.Column), task.Title)
+	}
+	fmt.Println()
+
+	if *dryRun {
+		fmt.Println("Dry run: no changes made.")
+		os.Exit(0)
+	}
+	if !*yes && !confirm(fmt.Sprintf("Apply to %d task(s)?", len(tasks))) {
+		fmt.Println("Aborted.")
+		os.Exit(1)
+	}
+
+	// Apply
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+
+	svc := service.NewTaskService(db)
+	if action.delete {
+		deleted, err := svc.DeleteMany(ids)
+		if err != nil {
+			exitWithError("delete tasks", err)
+		}
+		fmt.Printf("Deleted %d task(s)", len(ids))
+		if deleted > len(ids) {
+			fmt.Printf(" and %d subtask(s)", deleted-len(ids))
+		}
+		fmt.Println()
+		os.Exit(0)
+	}
+
+	updated, err := svc.UpdateMany(ids, action.patch)
+	if err != nil {
+		exitWithError("update tasks", err)
+	}
+	fmt.Printf(action.summary+"\n", len(updated))
+
+	os.Exit(0)
+}
+
+// parseBulkAction parses the action and its arguments from the front of
+// args, returning the remaining arguments. Exits on invalid input.
+func parseBulkAction(args []string, usage func()) (bulkAction, []string) {
+	fail := func(format string, a ...interface{}) {
+		fmt.Fprintf(os.Stderr, "Error: "+format+"\n", a...)
+		usage()
+		os.Exit(2)
+	}
+
+	if len(args) < 1 || strings.HasPrefix(args[0], "-") {
+		fail("Action is required")
+	}
+
+	var action bulkAction
+	switch args[0] {
+	case "move":
+		if len(args) < 2 {
+			fail("move requires a column")
+		}
+		column := args[1]
+		if !models.IsValidColumn(column) {
+			fail("Invalid column '%s'. Valid columns: %s", column, strings.Join(models.ValidColumns(), ", "))
+		}
+		action.patch.Column = &column
+		action.description = "move to " + formatColumnName(column)
+		action.summary = "Moved %d task(s) to " + formatColumnName(column)
+		return action, args[2:]
+
+	case "tag":
+		if len(args) < 3 || (args[1] != "add" && args[1] != "remove") {
+			fail("tag requires 'add' or 'remove' and comma-separated tags")
+		}
+		tags := strings.Split(args[2], ",")
+		tagList := strings.ReplaceAll(args[2], "%", "%%") // Used in the summary format
+		if args[1] == "add" {
+			action.patch.AddTags = tags
+			action.description = "add tags " + args[2] + " to"
+			action.summary = "Added tags " + tagList + " to %d task(s)"
+		} else {
+			action.patch.RemoveTags = tags
+			action.description = "remove tags " + args[2] + " from"
+			action.summary = "Removed tags " + tagList + " from %d task(s)"
+		}
+		return action, args[3:]
+
+	case "priority":
+		if len(args) < 2 {
+			fail("priority requires a value from 1 to 5")
+		}
+		priority, err := strconv.Atoi(args[1])
+		if err != nil || priority < 1 || priority > 5 {
+			fail("Priority must be between 1 and 5")
+		}
+		action.patch.Priority = &priority
+		action.description = fmt.Sprintf("set priority P%d on", priority)
+		action.summary = fmt.Sprintf("Set priority P%d on ", priority) + "%d task(s)"
+		return action, args[2:]
+
+	case "archive":
+		archived := true
+		action.patch.Archived = &archived
+		action.description = "archive"
+		action.summary = "Archived %d task(s)"
+		return action, args[1:]
+
+	case "delete":
+		action.delete = true
+		action.description = "delete"
+		return action, args[1:]
+	}
+
+	fail("Unknown action '%s'", args[0])
+	return action, nil
+}
+
+// readTasks resolves one task reference per line of r. The first word of
+// each line is used, with surrounding brackets removed, so 'ontop list'
+// output works. Blank lines and list headers are skipped.
+func readTasks(db *sql.DB, r io.Reader) []*models.Task {
+	svc := service.NewTaskService(db)
+	seen := make(map[string]bool)
+	var tasks []*models.Task
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == "-" {
+			fields = fields[1:] // Subtask marker in 'ontop list' output
+		}
+		if len(fields) == 0 || fields[0] == "Total:" || scanner.Text() == "No tasks found." {
+			continue
+		}
+
+		ref := strings.Trim(fields[0], "[]")
+		taskID := resolveTaskRef(db, ref)
+		if seen[taskID] {
+			continue
+		}
+		seen[taskID] = true
+
+		task, err := svc.Get(taskID)
+		if err != nil {
+			exitWithError("read tasks", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to read stdin: %v\n", err)
+		os.Exit(1)
+	}
+	return tasks
+}
@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/config"
+)
+
+// configUsage is the help text for 'ontop config'
+const configUsage = `Usage: ontop config <subcommand> [arguments]
+
+Read and change settings. Each layer overrides the previous one:
+    1. Built-in defaults
+    2. The global config file: %s
+    3. The project config file: .ontop.toml at the project root (see 'ontop init')
+    4. Environment variables: ONTOP_<KEY>, like ONTOP_UI_VIEW_MODE
+    5. Command line flags, like --board
+
+SUBCOMMANDS:
+    list                         Show every setting, its value and its layer
+    get <key>                    Print the value of a setting
+    set [-project] <key> <value> Save a setting to the global (or project) file
+    edit [-project]              Open the global (or project) file in $VISUAL/$EDITOR
+
+EXAMPLES:
+    ontop config list
+    ontop config set ui.view_mode row
+    ontop config set -project board work
+    ONTOP_UI_VIEW_MODE=row ontop
+`
+
+// ConfigCommand implements the 'ontop config' command. It does not use the
+// database.
+func ConfigCommand(args []string) {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, configUsage, config.GetConfigPath())
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		listConfig()
+
+	case "get":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: Usage: ontop config get <key>\n")
+			os.Exit(2)
+		}
+		setting, err := config.LookupSetting(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", capitalize(err.Error()))
+			os.Exit(2)
+		}
+		fmt.Println(setting.Value(loadConfigOrExit()))
+
+	case "set":
+		fs := flag.NewFlagSet("config set", flag.ExitOnError)
+		project := fs.Bool("project", false, "Save to the project config file")
+		if err := fs.Parse(args[1:]); err != nil {
+			os.Exit(2)
+		}
+		if fs.NArg() != 2 {
+			fmt.Fprintf(os.Stderr, "Error: Usage: ontop config set [-project] <key> <value>\n")
+			os.Exit(2)
+		}
+		path := configFilePath(*project)
+		if err := config.SetValue(path, fs.Arg(0), fs.Arg(1), *project); err != nil {
+			exitWithConfigError(err)
+		}
+		fmt.Printf("Set %s to %s in %s\n", fs.Arg(0), fs.Arg(1), path)
+
+	case "edit":
+		fs := flag.NewFlagSet("config edit", flag.ExitOnError)
+		project := fs.Bool("project", false, "Edit the project config file")
+		if err := fs.Parse(args[1:]); err != nil {
+			os.Exit(2)
+		}
+		editConfig(configFilePath(*project), *project)
+
+	case "-h", "-help", "--help", "help":
+		usage()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown subcommand '%s'\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(0)
+}
+
+func listConfig() {
+	fmt.Printf("# global:  %s\n", config.GetConfigPath())
+	if path := config.GetProjectConfigPath(); path != "" {
+		fmt.Printf("# project: %s\n", path)
+	} else {
+		fmt.Println("# project: none (not in a project)")
+	}
+	fmt.Println()
+
+	cfg, sources, err := config.LoadSources()
+	if err != nil {
+		exitWithConfigError(err)
+	}
+	for _, setting := range config.Settings() {
+		fmt.Printf("%-16s = %-12s # %s\n", setting.Key, setting.Literal(cfg), sources[setting.Key])
+	}
+	for _, action := range slices.Sorted(maps.Keys(cfg.Keys)) {
+		quoted := make([]string, len(cfg.Keys[action]))
+		for i, k := range cfg.Keys[action] {
+			quoted[i] = strconv.Quote(k)
+		}
+		key := "keys." + action
+		fmt.Printf("%-16s = %-12s # %s\n", key, "["+strings.Join(quoted, ", ")+"]", sources[key])
+	}
+}
+
+// configFilePath returns the global config file, or the project one
+func configFilePath(project bool) string {
+	if !project {
+		return config.GetConfigPath()
+	}
+	path := config.GetProjectConfigPath()
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Error: Not in a project. Create one with 'ontop init'.\n")
+		os.Exit(1)
+	}
+	return path
+}
+
+// editConfig opens a copy of a config file in the editor and saves it once
+// it is valid
+func editConfig(path string, project bool) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tmpFile, err := os.CreateTemp("", "ontop-config-*.toml")
+	if err != nil {
+		exitWithError("create temp file", err)
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		exitWithError("write temp file", err)
+	}
+
+	// Keep reopening the editor until the file is valid or the user gives up
+	for {
+		if err := runEditor(tmpPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nYour edits are in %s\n", err, tmpPath)
+			os.Exit(1)
+		}
+
+		edited, err := os.ReadFile(tmpPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nYour edits are in %s\n", err, tmpPath)
+			os.Exit(1)
+		}
+		err = config.CheckFile(path, edited, project)
+		if err == nil {
+			if string(edited) == string(data) {
+				fmt.Printf("No changes to %s\n", path)
+			} else if err := config.WriteFile(path, edited); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\nYour edits are in %s\n", err, tmpPath)
+				os.Exit(1)
+			} else {
+				fmt.Printf("Saved %s\n", path)
+			}
+			os.Remove(tmpPath)
+			return
+		}
+
+		printConfigError("Error", err)
+		if !confirm("Reopen the editor?") {
+			fmt.Fprintf(os.Stderr, "Your edits are in %s\n", tmpPath)
+			os.Exit(2)
+		}
+	}
+}
+
+// loadConfigOrExit loads the config, exiting if it is invalid
+func loadConfigOrExit() config.Config {
+	cfg, err := config.Load()
+	if err != nil {
+		exitWithConfigError(err)
+	}
+	return cfg
+}
+
+// exitWithConfigError reports invalid settings and exits with code 1
+func exitWithConfigError(err error) {
+	printConfigError("Error", err)
+	os.Exit(1)
+}
+
+// printConfigError reports invalid settings, one per line
+func printConfigError(prefix string, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, e)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%s: %v\n", prefix, err)
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// DeleteCommand implements the 'ontop delete' command
+func DeleteCommand(db *sql.DB, args []string) {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	force := fs.Bool("force", false, "Delete without asking for confirmation")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: ontop delete <task> [options]
+
+Delete a task and all of its subtasks. Asks for confirmation unless -force
+is given. The task can be a full ID, a unique ID prefix, a #N alias from the
+last 'ontop list', @last or @selected.
+
+OPTIONS:
+    -force    Delete without asking for confirmation
+
+EXAMPLES:
+    ontop delete 20251104-143000-00001
+    ontop delete '#3' -force
+`)
+	}
+
+	// Get task reference first (must be first argument)
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, "Error: Task ID is required\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	taskRef := args[0]
+
+	// Parse remaining args as flags
+	if err := fs.Parse(args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	taskID := resolveTaskRef(db, taskRef)
+	svc := service.NewTaskService(db)
+	task, err := svc.Get(taskID)
+	if err != nil {
+		exitWithError("delete task", err)
+	}
+
+	subtaskCount, err := storage.CountSubtasks(db, taskID)
+	if err != nil {
+		exitWithError("delete task", err)
+	}
+
+	if !*force {
+		prompt := fmt.Sprintf("Delete task %s: %s", task.ID, task.Title)
+		if subtaskCount > 0 {
+			prompt += fmt.Sprintf(" and its %d subtask(s)", subtaskCount)
+		}
+		if !confirm(prompt + "?") {
+			fmt.Println("Aborted.")
+			os.Exit(1)
+		}
+	}
+
+	if err := svc.Delete(taskID); err != nil {
+		exitWithError("delete task", err)
+	}
+
+	if subtaskCount > 0 {
+		fmt.Printf("Deleted task %s and %d subtask(s)\n", taskID, subtaskCount)
+	} else {
+		fmt.Printf("Deleted task %s\n", taskID)
+	}
+
+	os.Exit(0)
+}
+
+// confirm asks a yes/no question on stdin. Anything but y/yes is a no,
+// including end of input.
+func confirm(prompt string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// EditCommand implements the 'ontop edit' command
+func EditCommand(db *sql.DB, args []string) {
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: ontop edit <task>
+
+Open a task in $VISUAL or $EDITOR (default: vi) as a document with TOML front
+matter between '+++' lines, followed by the description. Fields you change are
+saved when the editor exits; fields you leave alone keep any changes made
+elsewhere in the meantime. The parent field accepts the same references as
+the task argument: a full ID, a unique ID prefix, #N, @last or @selected.
+
+EXAMPLES:
+    ontop edit 20251104-143000-00001
+    ontop edit '#2'
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Error: Task ID is required\n")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	taskID := resolveTaskRef(db, fs.Arg(0))
+	svc := service.NewTaskService(db)
+	task, err := svc.Get(taskID)
+	if err != nil {
+		exitWithError("edit task", err)
+	}
+
+	data, err := service.FormatTaskDocument(task)
+	if err != nil {
+		exitWithError("edit task", err)
+	}
+
+	tmpFile, err := os.CreateTemp("", "ontop-"+task.ID+"-*.md")
+	if err != nil {
+		exitWithError("create temp file", err)
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		exitWithError("write temp file", err)
+	}
+
+	// Keep reopening the editor until the document parses and saves, or the
+	// user gives up, so edits are never silently lost
+	for {
+		if err := runEditor(tmpPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\nYour edits are in %s\n", err, tmpPath)
+			os.Exit(1)
+		}
+
+		err := applyEdit(db, svc, task, tmpPath)
+		if err == nil {
+			os.Remove(tmpPath)
+			os.Exit(0)
+		}
+
+		var validationErr *service.ValidationError
+		if !errors.As(err, &validationErr) {
+			fmt.Fprintf(os.Stderr, "Your edits are in %s\n", tmpPath)
+			exitWithError("edit task", err)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %s\n", capitalize(validationErr.Message))
+		if !confirm("Reopen the editor?") {
+			fmt.Fprintf(os.Stderr, "Your edits are in %s\n", tmpPath)
+			os.Exit(2)
+		}
+	}
+}
+
+// applyEdit parses the edited document and saves the changed fields. If the
+// task changed elsewhere since it was loaded, the save is retried on top of
+// the latest version unless a field we changed was also changed there.
+func applyEdit(db *sql.DB, svc *service.TaskService, task *models.Task, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read edited task: %w", err)
+	}
+
+	doc, err := service.ParseTaskDocument(data)
+	if err != nil {
+		return err
+	}
+
+	patch := doc.Patch(task)
+	if patch.IsEmpty() {
+		fmt.Printf("No changes to task %s\n", task.ID)
+		return nil
+	}
+
+	if patch.ParentID != nil && *patch.ParentID != "" {
+		parentID, err := newResolver(db).Resolve(*patch.ParentID)
+		if err != nil {
+			// Let the user fix the reference in the editor
+			return &service.ValidationError{Message: fmt.Sprintf("invalid parent: %v", err)}
+		}
+		patch.ParentID = &parentID
+	}
+
+	version := task.Version
+	patch.Version = &version
+	_, err = svc.Update(task.ID, patch)
+	if errors.Is(err, storage.ErrConflict) {
+		latest, getErr := svc.Get(task.ID)
+		if getErr != nil {
+			return getErr
+		}
+		changedElsewhere := patchFields(service.NewTaskDocument(latest).Patch(task))
+		for _, field := range patchFields(patch) {
+			if slices.Contains(changedElsewhere, field) {
+				return fmt.Errorf("%w; its %s was also changed", err, field)
+			}
+		}
+		version = latest.Version
+		_, err = svc.Update(task.ID, patch)
+	}
+	if err != nil {
+		return err
+	}
+	rememberLastTask(db, task.ID)
+
+	fmt.Printf("Updated task %s: %s\n", task.ID, strings.Join(patchFields(patch), ", "))
+	return nil
+}
+
+// runEditor opens path in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Allow editors with arguments, like "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+	return nil
+}
+
+// patchFields names the fields a patch changes, for output
+func patchFields(patch service.TaskPatch) []string {
+	var fields []string
+	if patch.Title != nil {
+		fields = append(fields, "title")
+	}
+	if patch.Description != nil {
+		fields = append(fields, "description")
+	}
+	if patch.Priority != nil {
+		fields = append(fields, "priority")
+	}
+	if patch.Column != nil {
+		fields = append(fields, "column")
+	}
+	if patch.Progress != nil {
+		fields = append(fields, "progress")
+	}
+	if patch.Tags != nil {
+		fields = append(fields, "tags")
+	}
+	if patch.ParentID != nil {
+		fields = append(fields, "parent")
+	}
+	if patch.DueAt != nil {
+		fields = append(fields, "due")
+	}
+	return fields
+}
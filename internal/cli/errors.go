@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// exitWithError reports a TaskService error and exits. Invalid input exits
+// with code 2, like flag errors; everything else exits with code 1.
+func exitWithError(action string, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		fmt.Fprintf(os.Stderr, "Error: %s\n", capitalize(validationErr.Message))
+		os.Exit(2)
+	case errors.Is(err, service.ErrTaskNotFound):
+		fmt.Fprintf(os.Stderr, "Error: %s\n", capitalize(err.Error()))
+	case errors.Is(err, service.ErrBoardNotFound):
+		fmt.Fprintf(os.Stderr, "Error: %s. See 'ontop board list'.\n", capitalize(err.Error()))
+	case errors.Is(err, service.ErrViewNotFound):
+		fmt.Fprintf(os.Stderr, "Error: %s. See 'ontop views list'.\n", capitalize(err.Error()))
+	case errors.Is(err, storage.ErrConflict):
+		fmt.Fprintf(os.Stderr, "Error: %v. It was modified by another process while updating; review it with 'ontop show' and try again.\n", err)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Failed to %s: %v\n", action, err)
+	}
+	os.Exit(1)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// filterUsage documents the flags added by addFilterFlags
+const filterUsage = `    -priority int              Filter by priority (1-5)
+    -column string             Filter by column (inbox, in_progress, done)
+    -tag string                Filter by tag
+    -archived                  Select archived tasks instead of active tasks
+    -completed-after string    Only tasks completed on or after a date (YYYY-MM-DD)
+    -completed-before string   Only tasks completed before a date (YYYY-MM-DD)
+`
+
+// taskFilterFlags holds the task selection flags shared by list and bulk
+type taskFilterFlags struct {
+	priority        *int
+	column          *string
+	tag             *string
+	archived        *bool
+	completedAfter  *string
+	completedBefore *string
+}
+
+// addFilterFlags registers the task selection flags on fs
+func addFilterFlags(fs *flag.FlagSet) *taskFilterFlags {
+	return &taskFilterFlags{
+		priority:        fs.Int("priority", -1, "Filter by priority (1-5)"),
+		column:          fs.String("column", "", "Filter by column (inbox, in_progress, done)"),
+		tag:             fs.String("tag", "", "Filter by tag"),
+		archived:        fs.Bool("archived", false, "Select archived tasks"),
+		completedAfter:  fs.String("completed-after", "", "Only tasks completed on or after a date (YYYY-MM-DD)"),
+		completedBefore: fs.String("completed-before", "", "Only tasks completed before a date (YYYY-MM-DD)"),
+	}
+}
+
+// isSet reports whether any filter narrows the selection beyond the default
+// of all active tasks
+func (f *taskFilterFlags) isSet() bool {
+	return *f.priority > 0 || *f.column != "" || *f.tag != "" || *f.archived ||
+		*f.completedAfter != "" || *f.completedBefore != ""
+}
+
+// listTasks returns the tasks matching the filters, exiting on invalid
+// flags (code 2) or database errors (code 1)
+func (f *taskFilterFlags) listTasks(db *sql.DB) []*models.Task {
+	filters := map[string]interface{}{
+		"archived": *f.archived,
+		"board_id": currentBoard(db).ID,
+	}
+
+	if *f.priority > 0 {
+		if *f.priority > 5 {
+			fmt.Fprintf(os.Stderr, "Error: Priority must be between 1 and 5\n")
+			os.Exit(2)
+		}
+		filters["priority"] = *f.priority
+	}
+
+	if *f.column != "" {
+		filters["column"] = *f.column
+	}
+
+	if *f.tag != "" {
+		filters["tag"] = *f.tag
+	}
+
+	after := parseDateFlag("completed-after", *f.completedAfter)
+	before := parseDateFlag("completed-before", *f.completedBefore)
+
+	tasks, err := storage.ListTasks(db, filters)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to list tasks: %v\n", err)
+		os.Exit(1)
+	}
+
+	if after.IsZero() && before.IsZero() {
+		return tasks
+	}
+
+	var matched []*models.Task
+	for _, task := range tasks {
+		if task.CompletedAt == nil {
+			continue
+		}
+		if !after.IsZero() && task.CompletedAt.Before(after) {
+			continue
+		}
+		if !before.IsZero() && !task.CompletedAt.Before(before) {
+			continue
+		}
+		matched = append(matched, task)
+	}
+	return matched
+}
+
+// parseDateFlag parses a YYYY-MM-DD flag value as local midnight. An empty
+// value yields the zero time.
+func parseDateFlag(name, value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -%s must be a date like 2025-11-04\n", name)
+		os.Exit(2)
+	}
+	return date
+}
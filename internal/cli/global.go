@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucasefe/ontop/internal/config"
+)
+
+// ParseGlobalFlags parses the options given before the command, like
+// 'ontop --db-path work.db --board acme list', and returns the database path
+// and the command with its arguments. It runs before the command is
+// dispatched. --board selects the board for this run only, through
+// config.SetOverride, so the current board saved in the config file is kept.
+func ParseGlobalFlags(args []string) (dbPath string, rest []string) {
+	fs := flag.NewFlagSet("ontop", flag.ExitOnError)
+	fs.StringVar(&dbPath, "db-path", "", "Database file")
+	fs.Func("board", "Board to use for this command only", func(name string) error {
+		return config.SetOverride("board", name)
+	})
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: ontop [global options] [command] [arguments]
+
+Run without a command to open the kanban board.
+
+GLOBAL OPTIONS:
+    --db-path string    Database file (default: $%s, the project database
+                        or the global one; see 'ontop init -h')
+    --board string      Board to use for this command only, instead of the
+                        current one (see 'ontop board')
+
+EXAMPLES:
+    ontop --db-path ./sample-tasks.db
+    ontop --board default list
+`, config.DBPathEnv)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+	return dbPath, fs.Args()
+}
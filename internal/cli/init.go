@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// InitCommand implements the 'ontop init' command. It runs before any
+// database is opened, so it takes no *sql.DB.
+func InitCommand(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: ontop init [directory]
+
+Create a project database in .ontop/ontop.db under the directory (default:
+the current one). Commands and the TUI run anywhere below that directory
+then use it instead of the global database, like git finds its repository.
+
+The database is chosen in this order:
+    1. The --db-path flag
+    2. The $%s environment variable
+    3. The nearest project, found by walking up from the current directory:
+       a %s file (whose optional 'db' key sets the database path,
+       relative to the file) or a %s database
+    4. The global database, ontop.db in $XDG_CONFIG_HOME/ontop
+       (default: ~/.config/ontop)
+
+EXAMPLES:
+    ontop init
+    ontop init ~/src/myapp
+`, config.DBPathEnv, config.ProjectFileName, config.ProjectDBPath(""))
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	path := config.ProjectDBPath(dir)
+	if _, err := os.Stat(path); err == nil {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists\n", path)
+		os.Exit(1)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to create %s: %v\n", filepath.Dir(path), err)
+		os.Exit(1)
+	}
+	db, err := storage.NewDB(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = db.Close() }()
+	if err := storage.InitSchema(db); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Initialized ontop project database in %s\n", path)
+	fmt.Printf("Add %s/ to .gitignore to keep it out of version control.\n", config.ProjectDirName)
+}
@@ -6,19 +6,20 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
 	"github.com/lucasefe/ontop/internal/service"
-	"github.com/lucasefe/ontop/internal/storage"
 )
 
 // ListCommand implements the 'ontop list' command
 func ListCommand(db *sql.DB, args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
-	priority := fs.Int("priority", -1, "Filter by priority (1-5)")
-	column := fs.String("column", "", "Filter by column (inbox, in_progress, done)")
-	tag := fs.String("tag", "", "Filter by tag")
-	archived := fs.Bool("archived", false, "Show archived tasks")
+	filters := addFilterFlags(fs)
+	viewName := fs.String("view", "", "Show the tasks of a saved view")
 	jsonOutput := fs.Bool("json", false, "Output result as JSON")
 
 	fs.Usage = func() {
@@ -26,12 +27,14 @@ func ListCommand(db *sql.DB, args []string) {
 
 List tasks with optional filters.
 
+Each task is printed with a #N alias that other commands accept in place of
+its ID until the next 'ontop list', e.g. ontop show '#3'. Quote the alias:
+the shell treats an unquoted word starting with # as a comment.
+
 OPTIONS:
-    -priority int     Filter by priority (1-5)
-    -column string    Filter by column (inbox, in_progress, done)
-    -tag string       Filter by tag
-    -archived         Show archived tasks instead of active tasks
-    -json             Output result as JSON
+%s    -view string               Only tasks matching a saved view, in its sort
+                               order (see 'ontop views')
+    -json                      Output result as JSON
 
 EXAMPLES:
     ontop list
@@ -39,43 +42,31 @@ EXAMPLES:
     ontop list -column in_progress
     ontop list -tag urgent
     ontop list -archived
-`)
+    ontop list -column done -completed-after 2025-10-01
+    ontop list -view triage
+`, filterUsage)
 	}
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(2)
 	}
 
-	// Build filters
-	filters := make(map[string]interface{})
-
-	if *archived {
-		filters["archived"] = true
-	} else {
-		filters["archived"] = false
-	}
-
-	if *priority > 0 {
-		if *priority < 1 || *priority > 5 {
-			fmt.Fprintf(os.Stderr, "Error: Priority must be between 1 and 5\n")
-			os.Exit(2)
-		}
-		filters["priority"] = *priority
-	}
-
-	if *column != "" {
-		filters["column"] = *column
-	}
-
-	if *tag != "" {
-		filters["tag"] = *tag
-	}
-
 	// Query tasks
-	tasks, err := storage.ListTasks(db, filters)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to list tasks: %v\n", err)
-		os.Exit(1)
+	tasks := filters.listTasks(db)
+	sortMode := service.SortByPriority
+	if *viewName != "" {
+		view, err := service.NewViewService(db).Get(*viewName)
+		if err != nil {
+			exitWithError("load view", err)
+		}
+		filter, err := service.ParseFilter(view.Filter, time.Now())
+		if err != nil {
+			exitWithError("load view", err)
+		}
+		tasks = filter.Apply(tasks)
+		if i := slices.Index(config.SortModes, view.Sort); i >= 0 {
+			sortMode = service.SortMode(i)
+		}
 	}
 
 	// Output result
@@ -92,12 +83,22 @@ EXAMPLES:
 			os.Exit(0)
 		}
 
+		if board := currentBoard(db); board.ID != models.DefaultBoardID {
+			fmt.Printf("\nBoard: %s\n", board.Name)
+		}
 		fmt.Printf("\nTotal: %d tasks\n\n", len(tasks))
 
 		// Build hierarchical display order
-		hierarchical := service.BuildFlatHierarchy(tasks, service.SortByPriority)
+		hierarchical := service.BuildFlatHierarchy(tasks, sortMode)
 
-		for _, ht := range hierarchical {
+		// Number tasks in display order so they can be referenced as #N
+		aliases := make([]string, len(hierarchical))
+		for i, ht := range hierarchical {
+			aliases[i] = ht.Task.ID
+		}
+		rememberAliases(db, aliases)
+
+		for i, ht := range hierarchical {
 			// Add indentation for subtasks
 			indent := strings.Repeat(" ", ht.Indentation)
 			prefix := ""
@@ -114,6 +115,10 @@ EXAMPLES:
 			if ht.Task.Progress > 0 {
 				progressStr = fmt.Sprintf(" (%d%%)", ht.Task.Progress)
 			}
+			dueStr := ""
+			if ht.Task.DueAt != nil {
+				dueStr = fmt.Sprintf(" due %s", ht.Task.DueAt.Format("2006-01-02"))
+			}
 
 			// Display title if present, fallback to description
 			displayText := ht.Task.Description
@@ -121,15 +126,17 @@ EXAMPLES:
 				displayText = ht.Task.Title
 			}
 
-			fmt.Printf("%s%s[%s] %s | %s | %s%s%s\n",
+			fmt.Printf("%s%s[%s] #%d %s | %s | %s%s%s%s\n",
 				indent,
 				prefix,
 				ht.Task.ID,
+				i+1,
 				priorityStr,
 				formatColumnDisplay(ht.Task.Column),
 				displayText,
 				tagsStr,
 				progressStr,
+				dueStr,
 			)
 		}
 	}
@@ -5,11 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strings"
-	"time"
 
-	"github.com/lucasefe/ontop/internal/models"
-	"github.com/lucasefe/ontop/internal/storage"
+	"github.com/lucasefe/ontop/internal/service"
 )
 
 // MoveCommand implements the 'ontop move' command
@@ -17,9 +14,10 @@ func MoveCommand(db *sql.DB, args []string) {
 	fs := flag.NewFlagSet("move", flag.ExitOnError)
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Usage: ontop move <task-id> <column>
+		fmt.Fprintf(os.Stderr, `Usage: ontop move <task> <column>
 
-Move a task to a different column.
+Move a task to a different column. The task can be a full ID, a unique ID
+prefix, a #N alias from the last 'ontop list', @last or @selected.
 
 COLUMNS:
     inbox        - Inbox (new tasks)
@@ -29,6 +27,8 @@ COLUMNS:
 EXAMPLES:
     ontop move 20251104-143000-00001 in_progress
     ontop move 20251104-143000-00001 done
+    ontop move '#2' in_progress
+    ontop move @last done
 `)
 	}
 
@@ -43,43 +43,22 @@ EXAMPLES:
 		os.Exit(2)
 	}
 
-	taskID := fs.Arg(0)
+	taskID := resolveTaskRef(db, fs.Arg(0))
 	column := fs.Arg(1)
 
-	// Validate column
-	if !models.IsValidColumn(column) {
-		fmt.Fprintf(os.Stderr, "Error: Invalid column '%s'. Valid columns: %s\n",
-			column, strings.Join(models.ValidColumns(), ", "))
-		os.Exit(2)
-	}
-
 	// Get task
-	task, err := storage.GetTask(db, taskID)
+	svc := service.NewTaskService(db)
+	task, err := svc.Get(taskID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Task not found: %v\n", err)
-		os.Exit(1)
+		exitWithError("move task", err)
 	}
-
 	oldColumn := task.Column
-	task.Column = column
-	task.UpdatedAt = time.Now()
-
-	// If moving to done, set completed_at
-	if column == models.ColumnDone && task.CompletedAt == nil {
-		now := time.Now()
-		task.CompletedAt = &now
-	}
-
-	// If moving from done to another column, clear completed_at
-	if oldColumn == models.ColumnDone && column != models.ColumnDone {
-		task.CompletedAt = nil
-	}
 
-	// Update task
-	if err := storage.UpdateTask(db, task); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to move task: %v\n", err)
-		os.Exit(1)
+	// Move task (completed_at is kept in sync by the service)
+	if _, err := svc.Move(taskID, column); err != nil {
+		exitWithError("move task", err)
 	}
+	rememberLastTask(db, taskID)
 
 	fmt.Printf("Moved task %s: %s → %s\n",
 		taskID,
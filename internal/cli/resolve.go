@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// resolveTaskRef turns a task reference (ID prefix, #N alias, @last or
+// @selected) into a full task ID, exiting on failure. Ambiguous references
+// list the candidates and exit with code 2.
+func resolveTaskRef(db *sql.DB, ref string) string {
+	id, err := newResolver(db).Resolve(ref)
+	if err != nil {
+		var ambiguous *service.AmbiguousRefError
+		if errors.As(err, &ambiguous) {
+			fmt.Fprintf(os.Stderr, "Error: %s\nUse a longer prefix to pick one.\n", capitalize(err.Error()))
+			os.Exit(2)
+		}
+		exitWithError("resolve task", err)
+	}
+	return id
+}
+
+// newResolver creates a resolver with the aliases and references saved in
+// the session state of db
+func newResolver(db *sql.DB) *service.Resolver {
+	resolver := service.NewResolver(db)
+	if state, err := config.LoadState(dbFile(db)); err == nil {
+		resolver.Aliases = state.Aliases
+		resolver.Last = state.LastTaskID
+		resolver.Selected = state.SelectedTaskID
+	}
+	return resolver
+}
+
+// rememberLastTask records taskID as the target of @last. Failures are not
+// fatal: the command itself already succeeded.
+func rememberLastTask(db *sql.DB, taskID string) {
+	if err := config.UpdateState(dbFile(db), func(s *config.State) { s.LastTaskID = taskID }); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to save session state: %v\n", err)
+	}
+}
+
+// rememberAliases records the task IDs printed by 'ontop list' so they can be
+// referenced as #1, #2, ...
+func rememberAliases(db *sql.DB, taskIDs []string) {
+	if err := config.UpdateState(dbFile(db), func(s *config.State) { s.Aliases = taskIDs }); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to save session state: %v\n", err)
+	}
+}
+
+// dbFile returns the file db was opened on, which the session state belongs
+// to, or "" if it is unknown
+func dbFile(db *sql.DB) string {
+	path, err := storage.DBPath(db)
+	if err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lucasefe/ontop/internal/api"
+)
+
+// ServeCommand implements the 'ontop serve' command
+func ServeCommand(db *sql.DB, args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:7777", "Address to listen on")
+	token := fs.String("token", os.Getenv("ONTOP_API_TOKEN"), "Bearer token required on every request (optional)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Usage: ontop serve [options]
+
+Run a local JSON REST API for tasks. Safe to run alongside the TUI and CLI
+on the same database.
+
+OPTIONS:
+    -addr string     Address to listen on (default: 127.0.0.1:7777)
+    -token string    Bearer token required on every request
+                     (default: $ONTOP_API_TOKEN, empty disables auth)
+
+ENDPOINTS:
+    GET    /tasks                  List tasks (?column=&priority=&tag=&archived=true&board=)
+    POST   /tasks                  Create a task (on "board", default: the default board)
+    GET    /tasks/{id}             Get a task
+    PATCH  /tasks/{id}             Update title, description, priority, progress, tags, parent_id
+    DELETE /tasks/{id}             Delete a task and its subtasks
+    POST   /tasks/{id}/move        Move a task: {"column": "done"}
+    POST   /tasks/{id}/archive     Archive a task
+    POST   /tasks/{id}/unarchive   Unarchive a task
+    GET    /tasks/{id}/subtasks    List a task's subtasks
+    GET    /boards                 List boards
+    GET    /events                 Server-Sent Events stream of created, updated,
+                                   moved and deleted tasks (includes CLI/TUI changes)
+
+EXAMPLES:
+    ontop serve
+    ontop serve -addr 127.0.0.1:8080 -token s3cret
+    curl -H "Authorization: Bearer s3cret" http://127.0.0.1:8080/tasks?column=inbox
+    curl -N -H "Authorization: Bearer s3cret" http://127.0.0.1:8080/events
+`)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(2)
+	}
+
+	server := api.NewServer(db, *token)
+
+	authStatus := "disabled"
+	if *token != "" {
+		authStatus = "bearer token required"
+	}
+	fmt.Printf("Serving ontop API on http://%s (auth: %s)\n", *addr, authStatus)
+
+	if err := server.ListenAndServe(*addr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Server failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
@@ -18,16 +18,21 @@ func ShowCommand(db *sql.DB, args []string) {
 	jsonOutput := fs.Bool("json", false, "Output result as JSON")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Usage: ontop show <task-id> [options]
+		fmt.Fprintf(os.Stderr, `Usage: ontop show <task> [options]
 
 Show detailed information about a specific task, including all attributes and subtasks.
 
+The task can be a full ID, a unique ID prefix, a #N alias from the last
+'ontop list', @last or @selected.
+
 OPTIONS:
     -json    Output result as JSON
 
 EXAMPLES:
     ontop show 20251104-143000-00001
     ontop show 20251104-143000-00001 -json
+    ontop show 01K98X
+    ontop show '#3'
 `)
 	}
 
@@ -42,7 +47,7 @@ EXAMPLES:
 		os.Exit(2)
 	}
 
-	taskID := fs.Arg(0)
+	taskID := resolveTaskRef(db, fs.Arg(0))
 
 	// Get task
 	task, err := storage.GetTask(db, taskID)
@@ -109,6 +114,10 @@ EXAMPLES:
 			fmt.Printf("Parent:       %s\n", *task.ParentID)
 		}
 
+		if task.DueAt != nil {
+			fmt.Printf("Due:          %s\n", task.DueAt.Format("Mon 2006-01-02"))
+		}
+
 		fmt.Printf("\nCreated:      %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Updated:      %s\n", task.UpdatedAt.Format("2006-01-02 15:04:05"))
 
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// templateUsage is the help text for 'ontop template'
+const templateUsage = `Usage: ontop template <subcommand> [arguments]
+
+Manage task templates: a parent task with predefined subtasks, created in
+one step with 'ontop add -template <name>'. Templates are TOML files in
+%s; save one from an existing task or write it by hand.
+Titles and descriptions may use {{name}} variables, filled in with -var.
+
+SUBCOMMANDS:
+    list                        List templates and their variables
+    show <name>                 Print a template
+    save <task> <name> [-force] Save a task and its subtasks as a template
+    delete <name>               Delete a template
+
+TEMPLATE FILE:
+    title = "Release {{version}}"
+    description = "Ship {{version}} to production"
+    priority = 2                # Optional, default 3
+    tags = ["release"]          # Optional
+
+    [[subtasks]]
+    title = "Tag v{{version}}"  # Subtasks also take description, priority,
+                                # tags and column (default: the parent's)
+
+EXAMPLES:
+    ontop template save '#3' release
+    ontop template list
+    ontop add -template release -var version=1.4
+`
+
+// TemplateCommand implements the 'ontop template' command
+func TemplateCommand(db *sql.DB, args []string) {
+	dir := config.GetTemplatesDir()
+	usage := func() {
+		fmt.Fprintf(os.Stderr, templateUsage, dir)
+	}
+
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		listTemplates(dir)
+	case "show":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: Usage: ontop template show <name>\n")
+			os.Exit(2)
+		}
+		showTemplate(dir, args[1])
+	case "save":
+		saveTemplate(db, dir, args[1:])
+	case "delete", "rm":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: Usage: ontop template delete <name>\n")
+			os.Exit(2)
+		}
+		if err := service.DeleteTemplate(dir, args[1]); err != nil {
+			exitWithTemplateError(err)
+		}
+		fmt.Printf("Deleted template %s\n", args[1])
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown subcommand '%s'\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(0)
+}
+
+func listTemplates(dir string) {
+	names, err := service.ListTemplates(dir)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+	if len(names) == 0 {
+		fmt.Printf("No templates found in %s\n", dir)
+		return
+	}
+
+	for _, name := range names {
+		tmpl, err := service.LoadTemplate(dir, name)
+		if err != nil {
+			fmt.Printf("%-20s (invalid: %v)\n", name, err)
+			continue
+		}
+		vars := ""
+		if names := tmpl.Variables(); len(names) > 0 {
+			vars = fmt.Sprintf(" [vars: %s]", strings.Join(names, ", "))
+		}
+		fmt.Printf("%-20s %s (%d subtasks)%s\n", name, tmpl.Title, len(tmpl.Subtasks), vars)
+	}
+}
+
+func showTemplate(dir, name string) {
+	tmpl, err := service.LoadTemplate(dir, name)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+	data, err := service.FormatTemplate(tmpl)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+	fmt.Print(string(data))
+}
+
+func saveTemplate(db *sql.DB, dir string, args []string) {
+	fs := flag.NewFlagSet("template save", flag.ExitOnError)
+	force := fs.Bool("force", false, "Overwrite an existing template")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ontop template save <task> <name> [-force]\n")
+	}
+
+	// Flags may come before or after the arguments
+	var positional []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(2)
+		}
+		rest = fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		rest = rest[1:]
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	taskID := resolveTaskRef(db, positional[0])
+	task, err := service.NewTaskService(db).Get(taskID)
+	if err != nil {
+		exitWithError("save template", err)
+	}
+	if task.ParentID != nil {
+		fmt.Fprintf(os.Stderr, "Error: Task %s is a subtask; save its parent instead\n", taskID)
+		os.Exit(2)
+	}
+
+	allTasks, err := storage.ListTasks(db, map[string]interface{}{"include_archived": true})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to list subtasks: %v\n", err)
+		os.Exit(1)
+	}
+	var subtasks []*models.Task
+	for _, t := range allTasks {
+		if t.ParentID != nil && *t.ParentID == taskID {
+			subtasks = append(subtasks, t)
+		}
+	}
+	slices.SortFunc(subtasks, func(a, b *models.Task) int { // Oldest first
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+
+	path, err := service.SaveTemplate(dir, positional[1], service.NewTemplate(task, subtasks), *force)
+	if err != nil {
+		exitWithTemplateError(err)
+	}
+	fmt.Printf("Saved template %s with %d subtasks to %s\n", positional[1], len(subtasks), path)
+	fmt.Println("Edit it to replace specific values with {{variables}}.")
+}
+
+// exitWithTemplateError reports a template error and exits like exitWithError
+func exitWithTemplateError(err error) {
+	if errors.Is(err, service.ErrTemplateNotFound) {
+		fmt.Fprintf(os.Stderr, "Error: %s. See 'ontop template list'.\n", capitalize(err.Error()))
+		os.Exit(1)
+	}
+	exitWithError("use template", err)
+}
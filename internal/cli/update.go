@@ -9,12 +9,13 @@ import (
 	"time"
 
 	"github.com/lucasefe/ontop/internal/models"
-	"github.com/lucasefe/ontop/internal/storage"
+	"github.com/lucasefe/ontop/internal/service"
 )
 
 // UpdateCommand implements the 'ontop update' command
 func UpdateCommand(db *sql.DB, args []string) {
 	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	title := fs.String("title", "", "Update task title")
 	description := fs.String("description", "", "Update task description")
 	priority := fs.Int("priority", -1, "Update task priority (1-5)")
 	column := fs.String("column", "", "Update task column (inbox, in_progress, done)")
@@ -23,13 +24,18 @@ func UpdateCommand(db *sql.DB, args []string) {
 	addTagsStr := fs.String("add-tags", "", "Add tags (comma-separated)")
 	removeTagsStr := fs.String("remove-tags", "", "Remove tags (comma-separated)")
 	clearTags := fs.Bool("clear-tags", false, "Clear all tags")
+	parent := fs.String("parent", "", "Make this a subtask of another task")
+	noParent := fs.Bool("no-parent", false, "Make this a top-level task")
+	due := fs.String("due", "", "Update due date (today, tomorrow, a weekday, 3d, 2w, YYYY-MM-DD or none)")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Usage: ontop update <task-id> [options]
+		fmt.Fprintf(os.Stderr, `Usage: ontop update <task> [options]
 
-Update various attributes of a task.
+Update various attributes of a task. The task can be a full ID, a unique ID
+prefix, a #N alias from the last 'ontop list', @last or @selected.
 
 OPTIONS:
+    -title string         Update task title
     -description string   Update task description
     -priority int         Update task priority (1-5)
     -column string        Update task column (inbox, in_progress, done)
@@ -38,8 +44,12 @@ OPTIONS:
     -add-tags string      Add tags (comma-separated)
     -remove-tags string   Remove tags (comma-separated)
     -clear-tags           Clear all tags
+    -parent string        Make this a subtask of another task (ID, ID prefix, #N, @last or @selected)
+    -no-parent            Make this a top-level task
+    -due string           Update due date (today, tomorrow, a weekday, 3d, 2w, YYYY-MM-DD or none)
 
 EXAMPLES:
+    ontop update 20251104-143000-00001 -title "Ship the release"
     ontop update 20251104-143000-00001 -description "New description"
     ontop update 20251104-143000-00001 -priority 1
     ontop update 20251104-143000-00001 -progress 50
@@ -47,6 +57,12 @@ EXAMPLES:
     ontop update 20251104-143000-00001 -add-tags "urgent,bug"
     ontop update 20251104-143000-00001 -remove-tags "old-tag"
     ontop update 20251104-143000-00001 -priority 2 -progress 75
+    ontop update 01K98X -progress 100
+    ontop update '#1' -add-tags "urgent"
+    ontop update '#4' -parent '#1'
+    ontop update '#4' -no-parent
+    ontop update '#2' -due fri
+    ontop update '#2' -due none
 `)
 	}
 
@@ -57,7 +73,7 @@ EXAMPLES:
 		os.Exit(2)
 	}
 
-	taskID := args[0]
+	taskRef := args[0]
 
 	// Parse remaining args as flags
 	if err := fs.Parse(args[1:]); err != nil {
@@ -65,145 +81,111 @@ EXAMPLES:
 	}
 
 	// Get task
-	task, err := storage.GetTask(db, taskID)
+	taskID := resolveTaskRef(db, taskRef)
+	svc := service.NewTaskService(db)
+	task, err := svc.Get(taskID)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Task not found: %v\n", err)
-		os.Exit(1)
+		exitWithError("update task", err)
 	}
 
-	// Track what was updated
+	// Build patch and track what was updated
+	var patch service.TaskPatch
 	updates := []string{}
 
+	// Update title
+	if *title != "" {
+		patch.Title = title
+		updates = append(updates, "title")
+	}
+
 	// Update description
 	if *description != "" {
-		task.Description = *description
+		patch.Description = description
 		updates = append(updates, "description")
 	}
 
 	// Update priority
 	if *priority > 0 {
-		if *priority < 1 || *priority > 5 {
-			fmt.Fprintf(os.Stderr, "Error: Priority must be between 1 and 5\n")
-			os.Exit(2)
-		}
-		task.Priority = *priority
+		patch.Priority = priority
 		updates = append(updates, fmt.Sprintf("priority to P%d", *priority))
 	}
 
 	// Update column
 	if *column != "" {
-		if !models.IsValidColumn(*column) {
-			fmt.Fprintf(os.Stderr, "Error: Invalid column '%s'. Valid columns: %s\n",
-				*column, strings.Join(models.ValidColumns(), ", "))
-			os.Exit(2)
-		}
-		oldColumn := task.Column
-		task.Column = *column
-
-		// If moving to done, set completed_at
-		if *column == models.ColumnDone && task.CompletedAt == nil {
-			now := time.Now()
-			task.CompletedAt = &now
-		}
-
-		// If moving from done to another column, clear completed_at
-		if oldColumn == models.ColumnDone && *column != models.ColumnDone {
-			task.CompletedAt = nil
-		}
-
+		patch.Column = column
 		updates = append(updates, fmt.Sprintf("column to %s", *column))
 	}
 
 	// Update progress
 	if *progress >= 0 {
-		if *progress < 0 || *progress > 100 {
-			fmt.Fprintf(os.Stderr, "Error: Progress must be between 0 and 100\n")
-			os.Exit(2)
-		}
-		task.Progress = *progress
+		patch.Progress = progress
 		updates = append(updates, fmt.Sprintf("progress to %d%%", *progress))
-
-		// Auto-move to done if progress is 100
-		if *progress == 100 && task.Column != models.ColumnDone {
-			task.Column = models.ColumnDone
-			now := time.Now()
-			task.CompletedAt = &now
-			updates = append(updates, "auto-moved to Done")
-		}
 	}
 
 	// Handle tags
 	if *clearTags {
-		task.Tags = []string{}
+		patch.Tags = &[]string{}
 		updates = append(updates, "cleared all tags")
 	} else if *tagsStr != "" {
 		// Replace all tags
 		tags := strings.Split(*tagsStr, ",")
-		for i, tag := range tags {
-			tags[i] = strings.TrimSpace(tag)
-		}
-		task.Tags = tags
+		patch.Tags = &tags
 		updates = append(updates, "updated tags")
 	} else {
-		// Add tags
 		if *addTagsStr != "" {
-			newTags := strings.Split(*addTagsStr, ",")
-			for _, tag := range newTags {
-				tag = strings.TrimSpace(tag)
-				// Check if tag already exists
-				exists := false
-				for _, existing := range task.Tags {
-					if existing == tag {
-						exists = true
-						break
-					}
-				}
-				if !exists {
-					task.Tags = append(task.Tags, tag)
-				}
-			}
+			patch.AddTags = strings.Split(*addTagsStr, ",")
 			updates = append(updates, fmt.Sprintf("added tags: %s", *addTagsStr))
 		}
-
-		// Remove tags
 		if *removeTagsStr != "" {
-			removeTags := strings.Split(*removeTagsStr, ",")
-			for i, tag := range removeTags {
-				removeTags[i] = strings.TrimSpace(tag)
-			}
-
-			// Filter out removed tags
-			var newTags []string
-			for _, tag := range task.Tags {
-				keep := true
-				for _, removeTag := range removeTags {
-					if tag == removeTag {
-						keep = false
-						break
-					}
-				}
-				if keep {
-					newTags = append(newTags, tag)
-				}
-			}
-			task.Tags = newTags
+			patch.RemoveTags = strings.Split(*removeTagsStr, ",")
 			updates = append(updates, fmt.Sprintf("removed tags: %s", *removeTagsStr))
 		}
 	}
 
+	// Handle parent (existence and nesting are validated by the service)
+	if *parent != "" && *noParent {
+		fmt.Fprintf(os.Stderr, "Error: -parent and -no-parent cannot be used together\n")
+		os.Exit(2)
+	}
+	if *parent != "" {
+		parentID := resolveTaskRef(db, *parent)
+		patch.ParentID = &parentID
+		updates = append(updates, fmt.Sprintf("parent to %s", parentID))
+	} else if *noParent {
+		noParentID := "" // Empty clears the parent
+		patch.ParentID = &noParentID
+		updates = append(updates, "removed parent")
+	}
+
+	// Handle due date
+	if *due == "none" {
+		patch.DueAt = &time.Time{} // The zero time removes the due date
+		updates = append(updates, "removed due date")
+	} else if *due != "" {
+		dueAt, err := service.ParseDueDate(*due, time.Now())
+		if err != nil {
+			exitWithError("update task", err)
+		}
+		patch.DueAt = &dueAt
+		updates = append(updates, fmt.Sprintf("due date to %s", dueAt.Format("2006-01-02")))
+	}
+
 	// Check if anything was updated
 	if len(updates) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No updates specified. Use --help to see available options.\n")
 		os.Exit(2)
 	}
 
-	// Update timestamp
-	task.UpdatedAt = time.Now()
+	// Save task (validation and completion rules live in the service)
+	updated, err := svc.Update(taskID, patch)
+	if err != nil {
+		exitWithError("update task", err)
+	}
+	rememberLastTask(db, taskID)
 
-	// Save task
-	if err := storage.UpdateTask(db, task); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to update task: %v\n", err)
-		os.Exit(1)
+	if updated.Column == models.ColumnDone && task.Column != models.ColumnDone &&
+		(patch.Column == nil || *patch.Column != models.ColumnDone) {
+		updates = append(updates, "auto-moved to Done")
 	}
 
 	fmt.Printf("Updated task %s: %s\n", taskID, strings.Join(updates, ", "))
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// viewsUsage is the help text for 'ontop views'
+const viewsUsage = `Usage: ontop views <subcommand> [arguments]
+
+Views are named filters, saved with how to sort and lay out the tasks they
+select. They are shared by every board, and by 'ontop list -view' and the
+TUI, which cycles through them with F.
+
+SUBCOMMANDS:
+    list                                   List views
+    save <name> <filter> [-sort s] [-layout l]
+                                           Save a view, replacing one with the same name
+    delete <name>                          Delete a view
+
+OPTIONS (save):
+    -sort string      Sort order: priority, description, created or updated
+    -layout string    TUI layout: column or row
+
+FILTERS:
+    A task matches a filter when it matches every term. Prefix a term with -
+    to exclude the tasks it matches; put -- before a filter that starts
+    with -.
+
+    tag:name         Has the tag
+    priority:N       Has priority N; also <N, <=N, >N and >=N
+    column:name      Is in the column (inbox, in_progress, done)
+    due:when         Is due that day, like today, fri or 2025-11-07; also
+                     <, <=, >, >=, and due:none for tasks without a due date
+    word, "text"     Title or description contains the text, ignoring case
+
+EXAMPLES:
+    ontop views save triage "tag:bug priority:<=2 -tag:wontfix" -sort updated
+    ontop views save today "due:<=today -column:done" -layout row
+    ontop list -view triage
+    ontop views delete triage
+`
+
+// ViewsCommand implements the 'ontop views' command
+func ViewsCommand(db *sql.DB, args []string) {
+	usage := func() {
+		fmt.Fprint(os.Stderr, viewsUsage)
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	views := service.NewViewService(db)
+	switch args[0] {
+	case "list", "ls":
+		listViews(views)
+	case "save":
+		saveView(views, args[1:])
+	case "delete", "rm":
+		if len(args) != 2 {
+			fmt.Fprintf(os.Stderr, "Error: Usage: ontop views delete <name>\n")
+			os.Exit(2)
+		}
+		if err := views.Delete(args[1]); err != nil {
+			exitWithError("delete view", err)
+		}
+		fmt.Printf("Deleted view %s\n", args[1])
+	case "-h", "-help", "--help", "help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: Unknown subcommand '%s'\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(0)
+}
+
+func listViews(views *service.ViewService) {
+	list, err := views.List()
+	if err != nil {
+		exitWithError("list views", err)
+	}
+	if len(list) == 0 {
+		fmt.Println("No views found. Save one with 'ontop views save <name> <filter>'.")
+		return
+	}
+
+	for _, view := range list {
+		var details []string
+		if view.Sort != "" {
+			details = append(details, "sort: "+view.Sort)
+		}
+		if view.Layout != "" {
+			details = append(details, "layout: "+view.Layout)
+		}
+		detailsStr := ""
+		if len(details) > 0 {
+			detailsStr = fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+		}
+		fmt.Printf("%-20s %s%s\n", view.Name, view.Filter, detailsStr)
+	}
+}
+
+func saveView(views *service.ViewService, args []string) {
+	fs := flag.NewFlagSet("views save", flag.ExitOnError)
+	sort := fs.String("sort", "", "Sort order: priority, description, created or updated")
+	layout := fs.String("layout", "", "TUI layout: column or row")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ontop views save <name> <filter> [-sort s] [-layout l]\n")
+	}
+
+	// Flags may come before or after the arguments
+	var positional []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			os.Exit(2)
+		}
+		rest = fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		rest = rest[1:]
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	view, err := views.Save(&models.View{Name: positional[0], Filter: positional[1], Sort: *sort, Layout: *layout})
+	if err != nil {
+		exitWithError("save view", err)
+	}
+	fmt.Printf("Saved view %s. Use it with 'ontop list -view %s'.\n", view.Name, view.Name)
+}
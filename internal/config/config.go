@@ -1,133 +1,229 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/BurntSushi/toml"
+	"sync"
 )
 
-// Config represents the application configuration
+// Config represents the application configuration. Settings are layered,
+// each layer overriding the previous one:
+//
+//  1. Built-in defaults
+//  2. The global config file, ontop.toml in the config directory
+//  3. The project config file, .ontop.toml at the project root
+//  4. ONTOP_* environment variables, like ONTOP_UI_VIEW_MODE
+//  5. Command line flags, registered with SetOverride
 type Config struct {
-	UI UIConfig `toml:"ui"`
+	Board string   `toml:"board"` // Last used board name; empty for the default board
+	UI    UIConfig `toml:"ui"`
+
+	// Keys rebinds TUI actions, from the [keys] table: action name to the
+	// keys that trigger it. Files override each other per action.
+	Keys map[string][]string `toml:"-"`
 }
 
 // UIConfig holds user interface preferences
 type UIConfig struct {
-	ViewMode string `toml:"view_mode"` // "column" or "row"
+	ViewMode     string `toml:"view_mode"`     // "column" or "row"
+	Sort         string `toml:"sort"`          // One of SortModes
+	Swimlanes    string `toml:"swimlanes"`     // One of SwimlaneModes
+	ShowArchived bool   `toml:"show_archived"` // Show archived tasks instead of active
+	Theme        string `toml:"theme"`         // "auto", a built-in theme or a theme file name
 }
 
-// GetConfigPath returns the absolute path to the config file.
-// Follows XDG Base Directory specification: ~/.config/ontop/ontop.toml
-func GetConfigPath() string {
+// Names of the config layers, as reported by LoadSources
+const (
+	SourceDefault = "default"
+	SourceGlobal  = "global"
+	SourceProject = "project"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Default returns the built-in configuration
+func Default() Config {
+	return Config{
+		UI: UIConfig{
+			ViewMode:  "column",
+			Sort:      "priority",
+			Swimlanes: "none",
+			Theme:     "auto",
+		},
+	}
+}
+
+// GetConfigDir returns the directory holding the config file, session state
+// and templates: $XDG_CONFIG_HOME/ontop, or ~/.config/ontop if it is unset
+func GetConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "ontop")
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
-	return filepath.Join(home, ".config", "ontop", "ontop.toml")
+	return filepath.Join(home, ".config", "ontop")
 }
 
-// Load reads the config file from the standard location and returns
-// a Config struct. If the file doesn't exist or can't be parsed,
-// returns a Config with default values.
-//
-// Returns:
-//   - Config with loaded values (or defaults on error)
-//   - error (nil if successful or file not found, non-nil if parse failed)
-func Load() (Config, error) {
-	// Default config
-	cfg := Config{
-		UI: UIConfig{
-			ViewMode: "column",
-		},
+// GetConfigPath returns the absolute path to the global config file,
+// ontop.toml in the config directory
+func GetConfigPath() string {
+	dir := GetConfigDir()
+	if dir == "" {
+		return ""
 	}
+	return filepath.Join(dir, "ontop.toml")
+}
 
-	path := GetConfigPath()
-	if path == "" {
-		return cfg, fmt.Errorf("unable to determine home directory")
+// GetProjectConfigPath returns the config file of the project containing
+// the working directory, or "" outside a project. The file may not exist yet.
+func GetProjectConfigPath() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
 	}
-
-	// If file doesn't exist, return defaults (not an error)
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return cfg, nil
+	root, err := FindProject(cwd)
+	if err != nil || root == "" {
+		return ""
 	}
+	return filepath.Join(root, ProjectFileName)
+}
+
+// overrides holds settings from command line flags, by key
+var (
+	overrides   = map[string]string{}
+	overridesMu sync.Mutex
+)
 
-	// Try to parse the file
-	if _, err := toml.DecodeFile(path, &cfg); err != nil {
-		// Return defaults on parse error, but return the error so caller can log
-		return Config{
-			UI: UIConfig{
-				ViewMode: "column",
-			},
-		}, fmt.Errorf("failed to parse config file: %w", err)
+// SetOverride sets a setting for this process only, for global command line
+// flags like --board. Overrides take precedence over every other layer.
+func SetOverride(key, value string) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
 	}
+	scratch := Default()
+	if err := setting.set(&scratch, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	overrides[key] = value
+	return nil
+}
 
-	// Validate and sanitize view_mode
-	if cfg.UI.ViewMode != "column" && cfg.UI.ViewMode != "row" {
-		cfg.UI.ViewMode = "column"
+// GetTemplatesDir returns the directory holding task templates, one TOML
+// file per template: templates in the config directory
+func GetTemplatesDir() string {
+	dir := GetConfigDir()
+	if dir == "" {
+		return ""
 	}
+	return filepath.Join(dir, "templates")
+}
 
-	return cfg, nil
+// GetThemesDir returns the directory holding user-defined TUI themes, one
+// TOML file per theme: themes in the config directory
+func GetThemesDir() string {
+	dir := GetConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "themes")
 }
 
-// Save writes the config to the standard location. Creates the directory
-// if it doesn't exist. Uses atomic write (temp file + rename).
-//
-// Parameters:
-//   - cfg: The Config struct to persist
+// Load reads every config layer and returns the resulting Config. Files are
+// validated strictly: syntax errors, unknown keys and invalid values are
+// reported with their file and line.
 //
 // Returns:
-//   - error (nil if successful, non-nil if write failed)
-func Save(cfg Config) error {
-	path := GetConfigPath()
-	if path == "" {
-		return fmt.Errorf("unable to determine home directory")
-	}
+//   - Config with loaded values; invalid settings keep the previous layer's
+//     value, and a file that doesn't parse is skipped
+//   - error (nil if successful, non-nil if any layer is invalid)
+func Load() (Config, error) {
+	cfg, _, err := LoadSources()
+	return cfg, err
+}
 
-	// Create directory if it doesn't exist
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+// LoadSources is like Load, and also reports which layer each setting came
+// from, by key (see the Source constants)
+func LoadSources() (Config, map[string]string, error) {
+	cfg := Default()
+	sources := make(map[string]string)
+	for _, setting := range settings {
+		sources[setting.Key] = SourceDefault
 	}
 
-	// Create temp file in same directory
-	tmpFile, err := os.CreateTemp(dir, ".ontop.toml.tmp.*")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+	if GetConfigPath() == "" {
+		return cfg, sources, fmt.Errorf("unable to determine home directory")
 	}
-	tmpPath := tmpFile.Name()
 
-	// Ensure temp file is cleaned up on error
-	defer func() {
-		if tmpFile != nil {
-			tmpFile.Close()
-			os.Remove(tmpPath)
+	var errs []error
+	layers := []struct {
+		source  string
+		path    string
+		project bool
+	}{
+		{SourceGlobal, GetConfigPath(), false},
+		{SourceProject, GetProjectConfigPath(), true},
+	}
+	for _, layer := range layers {
+		if layer.path == "" {
+			continue
+		}
+		applied, err := decodeFile(layer.path, &cfg, layer.project)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, key := range applied {
+			sources[key] = layer.source
 		}
-	}()
-
-	// Encode config to TOML and write to temp file
-	encoder := toml.NewEncoder(tmpFile)
-	if err := encoder.Encode(cfg); err != nil {
-		return fmt.Errorf("failed to encode config: %w", err)
 	}
 
-	// Close temp file before rename
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
+	for _, setting := range settings {
+		value, ok := os.LookupEnv(setting.EnvVar())
+		if !ok {
+			continue
+		}
+		if err := setting.set(&cfg, value); err != nil {
+			errs = append(errs, fmt.Errorf("$%s: %w", setting.EnvVar(), err))
+			continue
+		}
+		sources[setting.Key] = SourceEnv
 	}
-	tmpFile = nil // Mark as closed so defer doesn't close again
 
-	// Atomic rename
-	if err := os.Rename(tmpPath, path); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	for key, value := range overrides {
+		setting, _ := LookupSetting(key) // Checked by SetOverride
+		_ = setting.set(&cfg, value)
+		sources[key] = SourceFlag
 	}
 
-	// Set permissions
-	if err := os.Chmod(path, 0644); err != nil {
-		// Non-fatal, just log
-		return fmt.Errorf("config saved but failed to set permissions: %w", err)
-	}
+	return cfg, sources, errors.Join(errs...)
+}
 
-	return nil
+// CheckFile validates the content of a config file without loading it, for
+// editors. project selects the rules of project config files, which may also
+// set the project database.
+func CheckFile(path string, data []byte, project bool) error {
+	cfg := Default()
+	_, err := decode(path, data, &cfg, project)
+	return err
+}
+
+// Save writes cfg to the global config file. Only settings that differ from
+// the file are rewritten, so comments and unknown keys are kept.
+//
+// Parameters:
+//   - cfg: The Config struct to persist
+//
+// Returns:
+//   - error (nil if successful, non-nil if write failed)
+func Save(cfg Config) error {
+	return Update(func(c *Config) { *c = cfg })
 }
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupLayers points the global config at a temp dir and runs the test in a
+// project, writing the given global and project config files ("" for none).
+// Overrides are cleared when the test ends.
+func setupLayers(t *testing.T, global, project string) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, setting := range settings {
+		t.Setenv(setting.EnvVar(), "")
+		os.Unsetenv(setting.EnvVar())
+	}
+	t.Cleanup(func() {
+		overridesMu.Lock()
+		defer overridesMu.Unlock()
+		overrides = map[string]string{}
+	})
+
+	if global != "" {
+		if err := os.MkdirAll(GetConfigDir(), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(GetConfigPath(), []byte(global), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root := t.TempDir()
+	if project != "" {
+		if err := os.WriteFile(filepath.Join(root, ProjectFileName), []byte(project), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(root)
+}
+
+// TestLoadSources tests each layer overrides the ones before it
+func TestLoadSources(t *testing.T) {
+	setupLayers(t,
+		"board = \"home\"\n[ui]\nview_mode = \"row\"\nsort = \"created\"\ntheme = \"nord\"\n",
+		"db = \"tasks.db\"\n[ui]\nsort = \"updated\"\ntheme = \"dracula\"\n",
+	)
+	t.Setenv("ONTOP_UI_THEME", "light")
+	if err := SetOverride("board", "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, sources, err := LoadSources()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key, value, source string
+	}{
+		{"ui.swimlanes", "none", SourceDefault},
+		{"ui.view_mode", "row", SourceGlobal},
+		{"ui.sort", "updated", SourceProject},
+		{"ui.theme", "light", SourceEnv},
+		{"board", "work", SourceFlag},
+	}
+	for _, tt := range tests {
+		setting, err := LookupSetting(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := setting.Value(cfg); got != tt.value || sources[tt.key] != tt.source {
+			t.Errorf("Expected %s = %q from %s, got %q from %s", tt.key, tt.value, tt.source, got, sources[tt.key])
+		}
+	}
+}
+
+// TestLoadSources_InvalidEnv tests an invalid environment variable is
+// reported and leaves the setting as the files set it
+func TestLoadSources_InvalidEnv(t *testing.T) {
+	setupLayers(t, "[ui]\nsort = \"created\"\n", "")
+	t.Setenv("ONTOP_UI_SORT", "random")
+	t.Setenv("ONTOP_UI_SHOW_ARCHIVED", "maybe")
+	t.Setenv("ONTOP_UI_VIEW_MODE", "row")
+
+	cfg, sources, err := LoadSources()
+	if err == nil {
+		t.Fatal("Expected an error for the invalid environment variables")
+	}
+	for _, want := range []string{`$ONTOP_UI_SORT: invalid sort "random"`, `$ONTOP_UI_SHOW_ARCHIVED: invalid boolean "maybe"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+	if cfg.UI.Sort != "created" || sources["ui.sort"] != SourceGlobal {
+		t.Errorf("Expected ui.sort from the global file, got %q from %s", cfg.UI.Sort, sources["ui.sort"])
+	}
+	if cfg.UI.ViewMode != "row" || sources["ui.view_mode"] != SourceEnv {
+		t.Errorf("Expected the valid variable to apply, got ui.view_mode %q from %s", cfg.UI.ViewMode, sources["ui.view_mode"])
+	}
+}
+
+// TestLoadSources_FileErrors tests invalid files are reported with their
+// path and line, while their valid settings still apply
+func TestLoadSources_FileErrors(t *testing.T) {
+	setupLayers(t,
+		"[ui]\nsort = \"created\"\ncolour = \"red\"\n",
+		"[ui]\nview_mode = \"grid\"\ntheme = \"nord\"\n",
+	)
+
+	cfg, _, err := LoadSources()
+	if err == nil {
+		t.Fatal("Expected an error for the invalid files")
+	}
+	for _, want := range []string{
+		GetConfigPath() + `:3: unknown key "ui.colour"`,
+		ProjectFileName + `:2: ui.view_mode: invalid view mode "grid"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error containing %q, got %v", want, err)
+		}
+	}
+	if cfg.UI.Sort != "created" || cfg.UI.Theme != "nord" || cfg.UI.ViewMode != "column" {
+		t.Errorf("Expected the valid settings to apply, got %+v", cfg.UI)
+	}
+}
+
+// TestSetOverride tests overrides are validated like any other layer
+func TestSetOverride(t *testing.T) {
+	setupLayers(t, "", "")
+	if err := SetOverride("ui.sort", "random"); err == nil {
+		t.Error("Expected an error for an invalid value")
+	}
+	if err := SetOverride("ui.colour", "red"); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+
+	cfg, sources, err := LoadSources()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.UI.Sort != "priority" || sources["ui.sort"] != SourceDefault {
+		t.Errorf("Expected the default sort, got %q from %s", cfg.UI.Sort, sources["ui.sort"])
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DBPathEnv is the environment variable that selects the database file
+const DBPathEnv = "ONTOP_DB"
+
+// Project databases live in a directory (usually a repository) marked by
+// either file below. Commands run anywhere inside it use its database.
+const (
+	ProjectDirName  = ".ontop"      // Holds the project database, ontop.db
+	ProjectFileName = ".ontop.toml" // Optional; may point at another database file
+)
+
+// Where the database path came from, in order of precedence
+const (
+	DBSourceFlag    = "flag"
+	DBSourceEnv     = DBPathEnv
+	DBSourceProject = "project"
+	DBSourceGlobal  = "global"
+)
+
+// ProjectFile is the content of a .ontop.toml project file: the project
+// database and settings that override the global config file
+type ProjectFile struct {
+	DB string `toml:"db"` // Database path, relative to the file; default .ontop/ontop.db
+	Config
+}
+
+// ResolveDBPath picks the database to open: the --db-path flag, else
+// $ONTOP_DB, else the nearest project database found by walking up from the
+// working directory, else the global database. An empty path means the
+// global default; storage.NewDB resolves an empty path with this.
+func ResolveDBPath(flagPath string) (path, source string, err error) {
+	if flagPath != "" {
+		return flagPath, DBSourceFlag, nil
+	}
+	if env := os.Getenv(DBPathEnv); env != "" {
+		return env, DBSourceEnv, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get working directory: %w", err)
+	}
+	path, err = FindProjectDB(cwd)
+	if err != nil {
+		return "", "", err
+	}
+	if path != "" {
+		return path, DBSourceProject, nil
+	}
+	return "", DBSourceGlobal, nil
+}
+
+// FindProject walks up from dir looking for a project root, like git looks
+// for .git: a directory with a .ontop.toml file or a .ontop/ontop.db
+// database. Returns an empty root if there is no project.
+func FindProject(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		for _, marker := range []string{filepath.Join(dir, ProjectFileName), ProjectDBPath(dir)} {
+			if _, err := os.Stat(marker); err == nil {
+				return dir, nil
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", err
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// FindProjectDB returns the database of the project containing dir, or an
+// empty path if there is no project. A .ontop.toml file may point at another
+// database than .ontop/ontop.db.
+func FindProjectDB(dir string) (string, error) {
+	root, err := FindProject(dir)
+	if root == "" || err != nil {
+		return "", err
+	}
+	return projectDBIn(root)
+}
+
+// projectDBIn returns the database of the project rooted at dir
+func projectDBIn(dir string) (string, error) {
+	file := filepath.Join(dir, ProjectFileName)
+	if _, err := os.Stat(file); err == nil {
+		var project ProjectFile
+		if _, err := toml.DecodeFile(file, &project); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		if project.DB == "" {
+			return ProjectDBPath(dir), nil
+		}
+		if filepath.IsAbs(project.DB) {
+			return project.DB, nil
+		}
+		return filepath.Join(dir, project.DB), nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	return ProjectDBPath(dir), nil
+}
+
+// ProjectDBPath returns where 'ontop init' puts the database of a project
+// rooted at dir: dir/.ontop/ontop.db
+func ProjectDBPath(dir string) string {
+	return filepath.Join(dir, ProjectDirName, "ontop.db")
+}
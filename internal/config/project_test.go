@@ -0,0 +1,223 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newProjectTree creates a directory tree for project discovery tests and
+// returns its root. files maps slash-separated paths to their content; a
+// path ending in / is a directory.
+func newProjectTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// TestFindProject tests the project root is found by walking up
+func TestFindProject(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		dir   string // Where to start, relative to the tree
+		want  string // Project root, relative to the tree; "" for none
+	}{
+		{
+			name:  "database in the directory",
+			files: map[string]string{".ontop/ontop.db": ""},
+			dir:   ".",
+			want:  ".",
+		},
+		{
+			name:  "database in a parent",
+			files: map[string]string{".ontop/ontop.db": "", "src/app/": ""},
+			dir:   "src/app",
+			want:  ".",
+		},
+		{
+			name:  "project file in a parent",
+			files: map[string]string{"repo/.ontop.toml": "", "repo/src/": ""},
+			dir:   "repo/src",
+			want:  "repo",
+		},
+		{
+			name:  "nearest project wins",
+			files: map[string]string{".ontop/ontop.db": "", "inner/.ontop.toml": "", "inner/src/": ""},
+			dir:   "inner/src",
+			want:  "inner",
+		},
+		{
+			name:  "an empty .ontop directory is not a project",
+			files: map[string]string{"repo/.ontop/": ""},
+			dir:   "repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newProjectTree(t, tt.files)
+			got, err := FindProject(filepath.Join(root, tt.dir))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			want := ""
+			if tt.want != "" {
+				want = filepath.Join(root, tt.want)
+			}
+			// The temp dir may be inside a project of the machine running
+			// the tests; only a project within the tree counts
+			if !strings.HasPrefix(got, root) {
+				got = ""
+			}
+			if got != want {
+				t.Errorf("Expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+// TestFindProjectDB tests the project database is the default one unless a
+// project file points elsewhere
+func TestFindProjectDB(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string // Relative to the tree, unless absolute
+		wantErr bool
+	}{
+		{
+			name:  "default database",
+			files: map[string]string{".ontop/ontop.db": ""},
+			want:  ".ontop/ontop.db",
+		},
+		{
+			name:  "project file without db",
+			files: map[string]string{".ontop.toml": "[ui]\nsort = \"updated\"\n"},
+			want:  ".ontop/ontop.db",
+		},
+		{
+			name:  "relative db",
+			files: map[string]string{".ontop.toml": "db = \"../shared/tasks.db\"\n"},
+			want:  "../shared/tasks.db",
+		},
+		{
+			name:  "absolute db",
+			files: map[string]string{".ontop.toml": "db = \"/var/lib/ontop.db\"\n"},
+			want:  "/var/lib/ontop.db",
+		},
+		{
+			name:    "invalid project file",
+			files:   map[string]string{".ontop.toml": "db = \n"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newProjectTree(t, tt.files)
+			got, err := FindProjectDB(root)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			want := tt.want
+			if !filepath.IsAbs(want) {
+				want = filepath.Join(root, want)
+			}
+			if got != want {
+				t.Errorf("Expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+// TestResolveDBPath tests the database is chosen by flag, then $ONTOP_DB,
+// then project, then the global default
+func TestResolveDBPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		env        string
+		project    bool // Whether the working directory is in a project
+		want       string
+		wantSource string
+	}{
+		{
+			name:       "flag first",
+			flag:       "/flag.db",
+			env:        "/env.db",
+			project:    true,
+			want:       "/flag.db",
+			wantSource: DBSourceFlag,
+		},
+		{
+			name:       "env over project",
+			env:        "/env.db",
+			project:    true,
+			want:       "/env.db",
+			wantSource: DBSourceEnv,
+		},
+		{
+			name:       "project over global",
+			project:    true,
+			want:       ".ontop/ontop.db",
+			wantSource: DBSourceProject,
+		},
+		{
+			name:       "global",
+			wantSource: DBSourceGlobal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"src/": ""}
+			if tt.project {
+				files[".ontop/ontop.db"] = ""
+			}
+			root := newProjectTree(t, files)
+			t.Chdir(filepath.Join(root, "src"))
+			t.Setenv(DBPathEnv, tt.env)
+
+			got, source, err := ResolveDBPath(tt.flag)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if source == DBSourceProject && !strings.HasPrefix(got, root) {
+				t.Skipf("Temp dir is inside a project: %s", got)
+			}
+			want := tt.want
+			if tt.wantSource == DBSourceProject {
+				want = filepath.Join(root, want)
+			}
+			if got != want || source != tt.wantSource {
+				t.Errorf("Expected %q from %s, got %q from %s", want, tt.wantSource, got, source)
+			}
+		})
+	}
+}
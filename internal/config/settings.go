@@ -0,0 +1,372 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Setting describes one config key, settable from files, the environment
+// and 'ontop config set'
+type Setting struct {
+	Key     string // Dotted key, like ui.view_mode
+	Doc     string
+	boolean bool // Written as a TOML boolean instead of a string
+	get     func(*Config) string
+	set     func(*Config, string) error // Validates the value
+}
+
+// settings lists every config key, in the order 'ontop config list' shows them
+var settings = []Setting{
+	{
+		Key: "board",
+		Doc: "Current board; empty for the default board",
+		get: func(c *Config) string { return c.Board },
+		set: func(c *Config, v string) error { c.Board = v; return nil },
+	},
+	{
+		Key: "ui.view_mode",
+		Doc: `Kanban layout: "column" or "row"`,
+		get: func(c *Config) string { return c.UI.ViewMode },
+		set: func(c *Config, v string) error {
+			if v != "column" && v != "row" {
+				return fmt.Errorf(`invalid view mode %q (want "column" or "row")`, v)
+			}
+			c.UI.ViewMode = v
+			return nil
+		},
+	},
+	{
+		Key: "ui.sort",
+		Doc: `Kanban sort order: "priority", "description", "created" or "updated"`,
+		get: func(c *Config) string { return c.UI.Sort },
+		set: func(c *Config, v string) error {
+			if !slices.Contains(SortModes, v) {
+				return fmt.Errorf("invalid sort %q (want one of %s)", v, strings.Join(SortModes, ", "))
+			}
+			c.UI.Sort = v
+			return nil
+		},
+	},
+	{
+		Key: "ui.swimlanes",
+		Doc: `Kanban swimlanes: "none", "priority", "tag" or "parent"`,
+		get: func(c *Config) string { return c.UI.Swimlanes },
+		set: func(c *Config, v string) error {
+			if !slices.Contains(SwimlaneModes, v) {
+				return fmt.Errorf("invalid swimlanes %q (want one of %s)", v, strings.Join(SwimlaneModes, ", "))
+			}
+			c.UI.Swimlanes = v
+			return nil
+		},
+	},
+	{
+		Key:     "ui.show_archived",
+		Doc:     "Show archived tasks instead of active ones in the kanban",
+		boolean: true,
+		get:     func(c *Config) string { return strconv.FormatBool(c.UI.ShowArchived) },
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			c.UI.ShowArchived = b
+			return nil
+		},
+	},
+	{
+		Key: "ui.theme",
+		Doc: `TUI colors: "auto" (by terminal background), a built-in theme or a file in the themes directory`,
+		get: func(c *Config) string { return c.UI.Theme },
+		set: func(c *Config, v string) error {
+			if !themeName.MatchString(v) {
+				return fmt.Errorf("invalid theme name %q (want letters, digits, '-' and '_')", v)
+			}
+			c.UI.Theme = v
+			return nil
+		},
+	},
+}
+
+// themeName matches valid ui.theme values, which may be file names
+var themeName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// SortModes are the valid values of ui.sort, in the order the TUI cycles
+// through them
+var SortModes = []string{"priority", "description", "created", "updated"}
+
+// SwimlaneModes are the valid values of ui.swimlanes, in the order the TUI
+// cycles through them
+var SwimlaneModes = []string{"none", "priority", "tag", "parent"}
+
+// Settings returns every config key
+func Settings() []Setting {
+	return slices.Clone(settings)
+}
+
+// LookupSetting returns the setting for key
+func LookupSetting(key string) (Setting, error) {
+	for _, setting := range settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown config key %q (see 'ontop config list')", key)
+}
+
+// Value returns the setting's value in cfg
+func (s Setting) Value(cfg Config) string {
+	return s.get(&cfg)
+}
+
+// EnvVar returns the environment variable that overrides the setting, like
+// ONTOP_UI_VIEW_MODE for ui.view_mode
+func (s Setting) EnvVar() string {
+	return "ONTOP_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(s.Key))
+}
+
+// Literal returns the setting's value in cfg as written in a config file
+func (s Setting) Literal(cfg Config) string {
+	literal, err := s.literal(&cfg)
+	if err != nil {
+		return strconv.Quote(s.get(&cfg))
+	}
+	return literal
+}
+
+// literal returns the setting's value in cfg as a TOML value
+func (s Setting) literal(cfg *Config) (string, error) {
+	if s.boolean {
+		return s.get(cfg), nil
+	}
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]string{"v": s.get(cfg)}); err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", s.Key, err)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(buf.String(), "v = ")), nil
+}
+
+func (s Setting) keyParts() []string {
+	return strings.Split(s.Key, ".")
+}
+
+// FileError is an invalid config file entry
+type FileError struct {
+	Path string
+	Line int // 0 if unknown
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// decodeFile reads the config file at path into cfg, like decode. A missing
+// file leaves cfg as is.
+func decodeFile(path string, cfg *Config, project bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return decode(path, data, cfg, project)
+}
+
+// decode parses a config file over cfg and returns the keys of the settings
+// it applied. Every unknown key and invalid value is reported; the valid
+// settings are applied regardless, unless the file doesn't parse at all.
+func decode(path string, data []byte, cfg *Config, project bool) ([]string, error) {
+	file := ProjectFile{Config: *cfg}
+	var md toml.MetaData
+	var err error
+	if project {
+		md, err = toml.Decode(string(data), &file)
+	} else {
+		md, err = toml.Decode(string(data), &file.Config)
+	}
+	if err != nil {
+		return nil, decodeError(path, err)
+	}
+
+	var applied []string
+	var errs []error
+	for _, key := range md.Undecoded() {
+		if key[0] == keysTable {
+			continue // Decoded by decodeKeys
+		}
+		errs = append(errs, &FileError{Path: path, Line: keyLine(data, key), Err: fmt.Errorf("unknown key %q", key.String())})
+	}
+	for _, setting := range settings {
+		if !md.IsDefined(setting.keyParts()...) {
+			continue
+		}
+		if err := setting.set(cfg, setting.get(&file.Config)); err != nil {
+			errs = append(errs, &FileError{Path: path, Line: keyLine(data, setting.keyParts()), Err: fmt.Errorf("%s: %w", setting.Key, err)})
+			continue
+		}
+		applied = append(applied, setting.Key)
+	}
+
+	keys, err := decodeKeys(path, data, cfg)
+	applied = append(applied, keys...)
+	errs = append(errs, err)
+	return applied, errors.Join(errs...)
+}
+
+// typeError matches the errors toml.Decode returns for values of the wrong
+// type, which only give their line in the message
+var typeError = regexp.MustCompile(`^toml: line (\d+) \(last key "(.*)"\): (.*)$`)
+
+// decodeError converts an error from toml.Decode to a FileError on the line
+// it happened
+func decodeError(path string, err error) error {
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) && parseErr.Position.Line > 0 {
+		msg := parseErr.Message
+		if msg == "" {
+			// Only the full message is set, starting with the position
+			prefix := fmt.Sprintf("toml: line %d: ", parseErr.Position.Line)
+			if parseErr.LastKey != "" {
+				prefix = fmt.Sprintf("toml: line %d (last key %q): ", parseErr.Position.Line, parseErr.LastKey)
+			}
+			msg = strings.TrimPrefix(parseErr.Error(), prefix)
+		}
+		return &FileError{Path: path, Line: parseErr.Position.Line, Err: errors.New(msg)}
+	}
+	if m := typeError.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &FileError{Path: path, Line: line, Err: fmt.Errorf("%s: %s", m[2], m[3])}
+	}
+	return &FileError{Path: path, Err: err}
+}
+
+// keysTable is the config table holding keybindings
+const keysTable = "keys"
+
+// decodeKeys applies the [keys] table of a config file to cfg.Keys and
+// returns the applied keys, like keys.archive. Each action takes a key or an
+// array of keys; which actions exist is up to the TUI.
+func decodeKeys(path string, data []byte, cfg *Config) ([]string, error) {
+	var file map[string]interface{}
+	if _, err := toml.Decode(string(data), &file); err != nil {
+		return nil, &FileError{Path: path, Err: err}
+	}
+	raw, ok := file[keysTable]
+	if !ok {
+		return nil, nil
+	}
+	table, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, &FileError{Path: path, Line: keyLine(data, toml.Key{keysTable}), Err: errors.New("keys must be a table of actions")}
+	}
+
+	names := make([]string, 0, len(table))
+	for name := range table {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	bindings := maps.Clone(cfg.Keys)
+	if bindings == nil {
+		bindings = make(map[string][]string)
+	}
+	var applied []string
+	var errs []error
+	for _, name := range names {
+		keys, err := keyList(table[name])
+		if err != nil {
+			errs = append(errs, &FileError{Path: path, Line: keyLine(data, toml.Key{keysTable, name}), Err: fmt.Errorf("%s.%s: %w", keysTable, name, err)})
+			continue
+		}
+		bindings[name] = keys
+		applied = append(applied, keysTable+"."+name)
+	}
+	cfg.Keys = bindings
+	return applied, errors.Join(errs...)
+}
+
+// keyList converts a [keys] value, a string or an array of strings, to a
+// list of keys. An empty array is allowed: it unbinds the action.
+func keyList(value interface{}) ([]string, error) {
+	var keys []string
+	switch v := value.(type) {
+	case string:
+		keys = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid key %v (want a string)", item)
+			}
+			keys = append(keys, s)
+		}
+	default:
+		return nil, fmt.Errorf("invalid value %v (want a key or an array of keys)", value)
+	}
+	if slices.Contains(keys, "") {
+		return nil, errors.New("keys can't be empty strings")
+	}
+	return keys, nil
+}
+
+// keyLine returns the line where key is set in a TOML document, or 0 if it
+// can't be found. It understands [table] headers and dotted keys, which is
+// all config files need.
+func keyLine(data []byte, key toml.Key) int {
+	want := key.String()
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(text, "[") {
+			name := strings.Trim(text, "[] \t")
+			if i := strings.Index(text, "]"); i > 0 {
+				name = strings.Trim(text[:i], "[] \t")
+			}
+			table = cleanKey(name)
+			if table == want {
+				return line
+			}
+			continue
+		}
+		name, _, ok := strings.Cut(text, "=")
+		if !ok || strings.HasPrefix(text, "#") {
+			continue
+		}
+		full := cleanKey(name)
+		if table != "" {
+			full = table + "." + full
+		}
+		if full == want {
+			return line
+		}
+	}
+	return 0
+}
+
+// cleanKey normalizes a written TOML key like `ui . "view_mode"` to ui.view_mode
+func cleanKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
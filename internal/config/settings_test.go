@@ -0,0 +1,148 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestDecodeKeys tests keybindings are read from the [keys] table
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		want    map[string][]string
+		wantErr string // Part of the error; "" for none
+	}{
+		{
+			name: "a key",
+			doc:  "[keys]\narchive = \"x\"\n",
+			want: map[string][]string{"archive": {"x"}},
+		},
+		{
+			name: "an array of keys",
+			doc:  "[keys]\narchive = [\"x\", \"ctrl+x\"]\n",
+			want: map[string][]string{"archive": {"x", "ctrl+x"}},
+		},
+		{
+			name: "an empty array unbinds",
+			doc:  "[keys]\narchive = []\n",
+			want: map[string][]string{"archive": nil},
+		},
+		{
+			name:    "empty key",
+			doc:     "[keys]\nquit = \"q\"\narchive = [\"x\", \"\"]\n",
+			want:    map[string][]string{"quit": {"q"}},
+			wantErr: "x.toml:3: keys.archive: keys can't be empty strings",
+		},
+		{
+			name:    "not a string",
+			doc:     "[keys]\narchive = [1]\n",
+			want:    map[string][]string{},
+			wantErr: "x.toml:2: keys.archive: invalid key 1",
+		},
+		{
+			name:    "not a table",
+			doc:     "keys = 1\n",
+			wantErr: "x.toml:1: keys must be a table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			_, err := decode("x.toml", []byte(tt.doc), &cfg, false)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if len(cfg.Keys) != len(tt.want) {
+				t.Fatalf("Expected keys %v, got %v", tt.want, cfg.Keys)
+			}
+			for name, keys := range tt.want {
+				got, ok := cfg.Keys[name]
+				if !ok || !slices.Equal(got, keys) {
+					t.Errorf("Expected keys.%s = %q, got %q", name, keys, got)
+				}
+			}
+		})
+	}
+}
+
+// TestDecode tests config files are validated strictly, reporting each
+// error with its file and line
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		project bool
+		applied []string
+		wantErr []string // Parts of the error; none if valid
+	}{
+		{
+			name:    "valid",
+			doc:     "board = \"work\"\n[ui]\nview_mode = \"row\"\nshow_archived = true\n",
+			applied: []string{"board", "ui.view_mode", "ui.show_archived"},
+		},
+		{
+			name:    "dotted keys",
+			doc:     "ui.sort = \"updated\"\n",
+			applied: []string{"ui.sort"},
+		},
+		{
+			name:    "unknown keys",
+			doc:     "[ui]\nsort = \"updated\"\n\n# Comment\ncolour = \"red\"\n[editor]\n",
+			applied: []string{"ui.sort"},
+			wantErr: []string{`x.toml:5: unknown key "ui.colour"`, `x.toml:6: unknown key "editor"`},
+		},
+		{
+			name:    "invalid values",
+			doc:     "[ui]\ntheme = \"nord\"\nsort = \"random\"\nswimlanes = \"status\"\n",
+			applied: []string{"ui.theme"},
+			wantErr: []string{`x.toml:3: ui.sort: invalid sort "random"`, `x.toml:4: ui.swimlanes: invalid swimlanes "status"`},
+		},
+		{
+			name:    "wrong type",
+			doc:     "[ui]\nshow_archived = \"yes\"\n",
+			wantErr: []string{"x.toml:2: ui.show_archived: incompatible types"},
+		},
+		{
+			name:    "syntax error",
+			doc:     "[ui]\nsort = priority\n",
+			wantErr: []string{`x.toml:2: expected value but found "priority" instead`},
+		},
+		{
+			name:    "db only in project files",
+			doc:     "db = \"tasks.db\"\n",
+			wantErr: []string{`x.toml:1: unknown key "db"`},
+		},
+		{
+			name:    "db in a project file",
+			doc:     "db = \"tasks.db\"\n",
+			project: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			applied, err := decode("x.toml", []byte(tt.doc), &cfg, tt.project)
+			if len(tt.wantErr) == 0 && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(tt.wantErr) > 0 && err == nil {
+				t.Fatalf("Expected errors %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error containing %q, got %v", want, err)
+				}
+			}
+			if !slices.Equal(applied, tt.applied) {
+				t.Errorf("Expected %v to apply, got %v", tt.applied, applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// State holds session data shared between CLI invocations and the TUI,
+// such as the numeric aliases printed by 'ontop list'. Unlike Config it is
+// written by the program, not edited by users. Each database has its own
+// state, since its task IDs mean nothing in another database.
+type State struct {
+	Aliases        []string `json:"aliases"`             // Task IDs by alias: #1 is Aliases[0]
+	LastTaskID     string   `json:"last_task_id"`        // Most recently created or modified task (@last)
+	SelectedTaskID string   `json:"selected_task_id"`    // Task selected in the TUI (@selected)
+	Collapsed      []string `json:"collapsed,omitempty"` // Parent tasks whose subtasks the TUI hides
+}
+
+// stateMu serializes read-modify-write cycles on the state file within a process
+var stateMu sync.Mutex
+
+// GetStatePath returns the absolute path to the session state file of the
+// database at dbPath: a file named after the database and a hash of its
+// path, in the state directory of the config directory
+func GetStatePath(dbPath string) string {
+	dir := GetConfigDir()
+	if dir == "" || dbPath == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(dbPath); err == nil {
+		dbPath = abs
+	}
+	sum := sha256.Sum256([]byte(dbPath))
+	name := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	return filepath.Join(dir, "state", fmt.Sprintf("%s-%x.json", name, sum[:8]))
+}
+
+// LoadState reads the session state of the database at dbPath. A missing
+// file yields an empty State.
+func LoadState(dbPath string) (State, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	return loadState(dbPath)
+}
+
+// UpdateStateAsync queues a state change like UpdateState without waiting
+// for it. Changes are saved in the order they are queued, on the goroutine
+// that writes config files; the channel receives the result.
+func UpdateStateAsync(dbPath string, fn func(*State)) <-chan error {
+	return queueWrite(writeRequest{path: dbPath, state: fn})
+}
+
+// UpdateState applies fn to the current state of the database at dbPath and
+// saves the result. Uses atomic write (temp file + rename).
+func UpdateState(dbPath string, fn func(*State)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadState(dbPath)
+	if err != nil {
+		// Start over rather than failing forever on a corrupt file
+		state = State{}
+	}
+	fn(&state)
+
+	path := GetStatePath(dbPath)
+	if path == "" {
+		return errNoStatePath
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".state.tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write state: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// errNoStatePath is returned when there is no state file: without a home
+// directory, or for a database not stored in a file
+var errNoStatePath = errors.New("unable to determine session state file")
+
+func loadState(dbPath string) (State, error) {
+	var state State
+
+	path := GetStatePath(dbPath)
+	if path == "" {
+		return state, errNoStatePath
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, fmt.Errorf("failed to parse state file: %w", err)
+	}
+	return state, nil
+}
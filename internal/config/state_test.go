@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestUpdateState tests each database keeps its own session state
+func TestUpdateState(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	work := filepath.Join(dir, "work", ".ontop", "ontop.db")
+	home := filepath.Join(dir, "home", ".ontop", "ontop.db")
+
+	if err := UpdateState(work, func(s *State) { s.Aliases = []string{"w1", "w2"}; s.LastTaskID = "w2" }); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-UpdateStateAsync(home, func(s *State) { s.SelectedTaskID = "h1" }); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := LoadState(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Aliases) != 2 || state.LastTaskID != "w2" || state.SelectedTaskID != "" {
+		t.Errorf("Unexpected state for %s: %+v", work, state)
+	}
+	state, err = LoadState(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Aliases) != 0 || state.LastTaskID != "" || state.SelectedTaskID != "h1" {
+		t.Errorf("Unexpected state for %s: %+v", home, state)
+	}
+
+	if GetStatePath(work) == GetStatePath(home) {
+		t.Errorf("Expected separate state files, got %s for both", GetStatePath(work))
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "work"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(dir, "work"))
+	if got := GetStatePath(filepath.Join(".ontop", "ontop.db")); got != GetStatePath(work) {
+		t.Errorf("Expected a relative path to share the state of %s, got %s", work, got)
+	}
+}
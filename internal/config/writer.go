@@ -0,0 +1,332 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config files, and state changes queued with UpdateStateAsync, are written
+// by a single goroutine, in the order writes are queued, so saves from the
+// TUI's background commands can't race or land out of order. Each write
+// re-reads the file and rewrites only the lines of the settings that
+// changed, keeping comments, formatting and keys this version doesn't know.
+var (
+	writes     chan writeRequest
+	writerOnce sync.Once
+)
+
+type writeRequest struct {
+	path    string // Config file, or the database whose state changes
+	project bool
+	fn      func(*Config) error // Changes the settings; nil to replace the file
+	force   []string            // Keys to write even if unchanged
+	data    []byte              // New content when fn is nil
+	state   func(*State)        // Changes the session state instead of a config file
+	done    chan error
+}
+
+// queueWrite hands a write to the writer goroutine
+func queueWrite(req writeRequest) <-chan error {
+	writerOnce.Do(func() {
+		writes = make(chan writeRequest, 64)
+		go func() {
+			for req := range writes {
+				req.done <- req.apply()
+			}
+		}()
+	})
+	req.done = make(chan error, 1)
+	writes <- req
+	return req.done
+}
+
+// Update applies fn to the settings in the global config file and saves the
+// ones it changed. Other layers are not written.
+func Update(fn func(*Config)) error {
+	return <-UpdateAsync(fn)
+}
+
+// UpdateAsync queues a write like Update without waiting for it. Writes
+// happen in the order they are queued; the channel receives the result.
+func UpdateAsync(fn func(*Config)) <-chan error {
+	path := GetConfigPath()
+	if path == "" {
+		return failed(fmt.Errorf("unable to determine home directory"))
+	}
+	return queueWrite(writeRequest{
+		path: path,
+		fn:   func(cfg *Config) error { fn(cfg); return nil },
+	})
+}
+
+// SetValue validates value for key and writes it to the config file at path,
+// keeping the other entries in the file
+func SetValue(path, key, value string, project bool) error {
+	setting, err := LookupSetting(key)
+	if err != nil {
+		return err
+	}
+	scratch := Default()
+	if err := setting.set(&scratch, value); err != nil {
+		return fmt.Errorf("%s: %w", key, err)
+	}
+
+	return <-queueWrite(writeRequest{
+		path:    path,
+		project: project,
+		fn:      func(cfg *Config) error { return setting.set(cfg, value) },
+		force:   []string{key},
+	})
+}
+
+// WriteFile replaces the config file at path with data. Creates the
+// directory if it doesn't exist. Uses atomic write (temp file + rename).
+func WriteFile(path string, data []byte) error {
+	return <-queueWrite(writeRequest{path: path, data: data})
+}
+
+func failed(err error) <-chan error {
+	done := make(chan error, 1)
+	done <- err
+	return done
+}
+
+// apply performs the write. It runs on the writer goroutine.
+func (req writeRequest) apply() error {
+	if req.state != nil {
+		return UpdateState(req.path, req.state)
+	}
+	if req.fn == nil {
+		return writeFile(req.path, req.data)
+	}
+
+	data, err := os.ReadFile(req.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Unknown keys and invalid values are left for the user to fix; only a
+	// file we can't parse is off limits
+	before := ProjectFile{Config: Default()}
+	var target interface{} = &before.Config
+	if req.project {
+		target = &before
+	}
+	if _, err := toml.Decode(string(data), target); err != nil {
+		return &FileError{Path: req.path, Err: fmt.Errorf("not saving to a file that doesn't parse: %w", err)}
+	}
+
+	after := before.Config
+	if err := req.fn(&after); err != nil {
+		return err
+	}
+
+	changed := false
+	for _, setting := range settings {
+		if setting.get(&before.Config) == setting.get(&after) && !slices.Contains(req.force, setting.Key) {
+			continue
+		}
+		literal, err := setting.literal(&after)
+		if err != nil {
+			return err
+		}
+		data = setKeyLine(data, setting.keyParts(), literal)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	// Never leave behind a file that no longer parses
+	var check map[string]interface{}
+	if _, err := toml.Decode(string(data), &check); err != nil {
+		return fmt.Errorf("failed to update %s: %w", req.path, err)
+	}
+	return writeFile(req.path, data)
+}
+
+// setKeyLine sets key to the TOML literal value in a document, editing the
+// key's line in place (keeping any trailing comment) or adding a line for it.
+// A document with CRLF line endings keeps them.
+func setKeyLine(data []byte, key []string, literal string) []byte {
+	newline := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	want := strings.Join(key, ".")
+	parent := strings.Join(key[:len(key)-1], ".")
+	leaf := key[len(key)-1]
+
+	table := ""
+	header := -1       // Line of the [parent] header
+	lastInParent := -1 // Last key line in the parent table
+	lastDotted := -1   // Last top-level "parent.x = " line
+	firstHeader := -1
+	lastTopLevel := -1
+	for i, line := range lines {
+		text := strings.TrimSpace(line)
+		if strings.HasPrefix(text, "[") {
+			name := strings.Trim(text, "[] \t")
+			if j := strings.Index(text, "]"); j > 0 {
+				name = strings.Trim(text[:j], "[] \t")
+			}
+			table = cleanKey(name)
+			if firstHeader < 0 {
+				firstHeader = i
+			}
+			if table == parent {
+				header = i
+			}
+			continue
+		}
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || strings.HasPrefix(text, "#") {
+			continue
+		}
+		full := cleanKey(name)
+		if table != "" {
+			full = table + "." + full
+		}
+
+		if full == want {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			lines[i] = indent + strings.TrimSpace(name) + " = " + literal + trailingComment(value)
+			return joinLines(lines, newline)
+		}
+
+		switch {
+		case table == "":
+			lastTopLevel = i
+			if parent != "" && strings.HasPrefix(full, parent+".") {
+				lastDotted = i
+			}
+		case table == parent:
+			lastInParent = i
+		}
+	}
+
+	insert := func(at int, added ...string) []byte {
+		lines = append(lines[:at], append(added, lines[at:]...)...)
+		return joinLines(lines, newline)
+	}
+	// New lines are indented like the line they follow
+	after := func(i int, entry string) []byte {
+		indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " \t"))]
+		return insert(i+1, indent+entry)
+	}
+	entry := leaf + " = " + literal
+
+	switch {
+	case parent == "" && lastTopLevel >= 0:
+		return after(lastTopLevel, entry)
+	case parent == "" && firstHeader >= 0:
+		return insert(firstHeader, entry, "")
+	case parent == "":
+		return insert(len(lines), entry)
+	case lastInParent >= 0:
+		return after(lastInParent, entry)
+	case header >= 0:
+		return insert(header+1, entry)
+	case lastDotted >= 0:
+		return after(lastDotted, parent+"."+entry)
+	}
+
+	if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+		lines = append(lines, "")
+	}
+	return insert(len(lines), "["+parent+"]", entry)
+}
+
+// trailingComment returns the "# ..." comment after a value, if any, with
+// the whitespace before it
+func trailingComment(value string) string {
+	var quote rune
+	escaped := false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return value[len(strings.TrimRight(value[:i], " \t")):]
+		}
+	}
+	return ""
+}
+
+func joinLines(lines []string, newline string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, newline) + newline)
+}
+
+// writeFile atomically replaces the file at path with data
+func writeFile(path string, data []byte) error {
+	// Create directory if it doesn't exist
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Create temp file in same directory
+	tmpFile, err := os.CreateTemp(dir, ".ontop.toml.tmp.*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	// Ensure temp file is cleaned up on error
+	defer func() {
+		if tmpFile != nil {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	// Close temp file before rename
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	tmpFile = nil // Mark as closed so defer doesn't close again
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	// Set permissions
+	if err := os.Chmod(path, 0644); err != nil {
+		// Non-fatal, just log
+		return fmt.Errorf("config saved but failed to set permissions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSetKeyLine tests keys are edited in place or added where they belong,
+// leaving the rest of the document as it was
+func TestSetKeyLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		key     string
+		literal string
+		want    string
+	}{
+		{
+			name:    "edit in place keeping the comment",
+			doc:     "# My settings\n[ui]\nsort = \"priority\"  # Or \"updated\"\ntheme = \"nord\"\n",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "# My settings\n[ui]\nsort = \"updated\"  # Or \"updated\"\ntheme = \"nord\"\n",
+		},
+		{
+			name:    "comment marker inside a string",
+			doc:     "[ui]\ntheme = \"#1\" # Note\n",
+			key:     "ui.theme",
+			literal: `"nord"`,
+			want:    "[ui]\ntheme = \"nord\" # Note\n",
+		},
+		{
+			name:    "insert into an existing table",
+			doc:     "[ui]\n  theme = \"nord\"\nunknown_key = 1\n\n[keys]\nquit = [\"q\"]\n",
+			key:     "ui.sort",
+			literal: `"created"`,
+			want:    "[ui]\n  theme = \"nord\"\nunknown_key = 1\nsort = \"created\"\n\n[keys]\nquit = [\"q\"]\n",
+		},
+		{
+			name:    "insert into an empty table",
+			doc:     "[ui]\n\n[keys]\n",
+			key:     "ui.sort",
+			literal: `"created"`,
+			want:    "[ui]\nsort = \"created\"\n\n[keys]\n",
+		},
+		{
+			name:    "top-level key before the first header",
+			doc:     "# Comment\n[ui]\nsort = \"priority\"\n",
+			key:     "board",
+			literal: `"work"`,
+			want:    "# Comment\nboard = \"work\"\n\n[ui]\nsort = \"priority\"\n",
+		},
+		{
+			name:    "top-level key after the other top-level keys",
+			doc:     "database_path = \"/tmp/db\"\n\n[ui]\n",
+			key:     "board",
+			literal: `"work"`,
+			want:    "database_path = \"/tmp/db\"\nboard = \"work\"\n\n[ui]\n",
+		},
+		{
+			name:    "edit a dotted key",
+			doc:     "ui.sort = \"priority\"\n",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "ui.sort = \"updated\"\n",
+		},
+		{
+			name:    "insert next to dotted keys",
+			doc:     "ui.theme = \"nord\"\nboard = \"work\"\n",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "ui.theme = \"nord\"\nui.sort = \"updated\"\nboard = \"work\"\n",
+		},
+		{
+			name:    "new table",
+			doc:     "board = \"work\"\n",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "board = \"work\"\n\n[ui]\nsort = \"updated\"\n",
+		},
+		{
+			name:    "new table in an empty file",
+			doc:     "",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "[ui]\nsort = \"updated\"\n",
+		},
+		{
+			name:    "CRLF line endings are kept",
+			doc:     "[ui]\r\nsort = \"priority\" # Comment\r\n",
+			key:     "ui.theme",
+			literal: `"nord"`,
+			want:    "[ui]\r\nsort = \"priority\" # Comment\r\ntheme = \"nord\"\r\n",
+		},
+		{
+			name:    "CRLF edit in place",
+			doc:     "[ui]\r\nsort = \"priority\" # Comment\r\n",
+			key:     "ui.sort",
+			literal: `"updated"`,
+			want:    "[ui]\r\nsort = \"updated\" # Comment\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(setKeyLine([]byte(tt.doc), strings.Split(tt.key, "."), tt.literal))
+			if got != tt.want {
+				t.Errorf("Expected:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestWriteRequest_InlineTable tests a setting in an inline table is not
+// updated, leaving the file untouched, since adding a [ui] table for it
+// would define the table twice
+func TestWriteRequest_InlineTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	doc := "ui = { sort = \"priority\" }\n"
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeRequest{path: path, fn: func(cfg *Config) error {
+		cfg.UI.Sort = "updated"
+		return nil
+	}}.apply()
+	if err == nil {
+		t.Fatal("Expected an error updating an inline table")
+	}
+	var fileErr *FileError
+	if errors.As(err, &fileErr) {
+		t.Errorf("Expected an update error, not a parse error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != doc {
+		t.Errorf("Expected the file to be unchanged, got %q", data)
+	}
+}
+
+// TestWriteRequest_KeepsUnknownKeys tests an update only rewrites the
+// settings that changed
+func TestWriteRequest_KeepsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	doc := "future_key = true # From a newer version\n\n[ui]\nsort = \"priority\"\n"
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeRequest{path: path, fn: func(cfg *Config) error {
+		cfg.UI.Sort = "updated"
+		return nil
+	}}.apply()
+	if err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "future_key = true # From a newer version\n\n[ui]\nsort = \"updated\"\n"
+	if string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+}
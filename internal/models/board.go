@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DefaultBoardID is the board tasks belong to unless another one is chosen.
+// Databases created before boards existed have all their tasks on it.
+const DefaultBoardID = "default"
+
+// Board groups tasks, like separate projects in one database
+type Board struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"` // Unique, case-insensitive
+	CreatedAt time.Time `json:"created_at"`
+}
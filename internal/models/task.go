@@ -5,6 +5,7 @@ import "time"
 // Task represents a work item with all attributes
 type Task struct {
 	ID          string     `json:"id"`
+	BoardID     string     `json:"board_id"`    // Board the task belongs to
 	Title       string     `json:"title"`       // Short title shown in kanban
 	Description string     `json:"description"` // Full text description
 	Priority    int        `json:"priority"`    // 1-5 where 1 is highest
@@ -17,4 +18,6 @@ type Task struct {
 	UpdatedAt   time.Time  `json:"updated_at"`
 	CompletedAt *time.Time `json:"completed_at"` // NULL if not completed
 	DeletedAt   *time.Time `json:"deleted_at"`   // NULL if not deleted
+	DueAt       *time.Time `json:"due_at"`       // NULL if no due date
+	Version     int        `json:"version"`      // Incremented on every update
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// View is a named task filter, saved to be used again along with how to
+// show the tasks it selects
+type View struct {
+	Name      string    `json:"name"`   // Unique, case-insensitive
+	Filter    string    `json:"filter"` // Filter expression, see service.ParseFilter
+	Sort      string    `json:"sort"`   // Sort order like ui.sort; empty keeps the current one
+	Layout    string    `json:"layout"` // "column" or "row"; empty keeps the current one
+	CreatedAt time.Time `json:"created_at"`
+}
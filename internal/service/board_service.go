@@ -0,0 +1,123 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// ErrBoardNotFound is returned when no board matches a name or ID
+var ErrBoardNotFound = errors.New("board not found")
+
+// maxBoardName is the longest allowed board name
+const maxBoardName = 50
+
+// BoardService owns the rules for boards, which group tasks into separate
+// projects within one database
+type BoardService struct {
+	db  *sql.DB
+	now func() time.Time // Clock, replaceable in tests
+}
+
+// NewBoardService creates a BoardService backed by db
+func NewBoardService(db *sql.DB) *BoardService {
+	return &BoardService{db: db, now: time.Now}
+}
+
+// List returns all boards, the default board first
+func (s *BoardService) List() ([]*models.Board, error) {
+	return storage.ListBoards(s.db)
+}
+
+// Get returns the board with the given name (ignoring case) or ID. An empty
+// ref is the default board.
+func (s *BoardService) Get(ref string) (*models.Board, error) {
+	return getBoard(s.db, ref)
+}
+
+// Create adds a board with a unique name
+func (s *BoardService) Create(name string) (*models.Board, error) {
+	board := &models.Board{ID: GenerateID(), CreatedAt: s.now()}
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		if board.Name, err = validateBoardName(tx, name, ""); err != nil {
+			return err
+		}
+		return storage.CreateBoard(tx, board)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// Rename gives a board a new unique name
+func (s *BoardService) Rename(ref, name string) (*models.Board, error) {
+	var board *models.Board
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		if board, err = getBoard(tx, ref); err != nil {
+			return err
+		}
+		if board.Name, err = validateBoardName(tx, name, board.ID); err != nil {
+			return err
+		}
+		return storage.RenameBoard(tx, board.ID, board.Name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// withTx runs fn in a transaction, committing on success
+func (s *BoardService) withTx(fn func(tx *sql.Tx) error) error {
+	return withTx(s.db, fn)
+}
+
+// Helper functions
+
+func getBoard(db storage.DBTX, ref string) (*models.Board, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		ref = models.DefaultBoardID
+	}
+
+	board, err := storage.GetBoardByName(db, ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		board, err = storage.GetBoard(db, ref)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrBoardNotFound, ref)
+	}
+	return board, err
+}
+
+// validateBoardName returns the trimmed name if it is valid and not used by
+// a board other than exceptID
+func validateBoardName(db storage.DBTX, name, exceptID string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalidf("board name is required")
+	}
+	if len(name) > maxBoardName {
+		return "", invalidf("board name must be at most %d characters", maxBoardName)
+	}
+	if strings.HasPrefix(name, "-") {
+		return "", invalidf("board name cannot start with '-'")
+	}
+
+	existing, err := storage.GetBoardByName(db, name)
+	if err == nil && existing.ID != exceptID {
+		return "", invalidf("a board named '%s' already exists", existing.Name)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	return name, nil
+}
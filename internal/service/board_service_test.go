@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestBoardService tests creating, finding and renaming boards
+func TestBoardService(t *testing.T) {
+	_, db := newTestService(t)
+	boards := NewBoardService(db)
+
+	work, err := boards.Create("  Work ")
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+	if work.Name != "Work" {
+		t.Errorf("Expected trimmed name 'Work', got %q", work.Name)
+	}
+
+	for _, name := range []string{"", "work", "-x"} {
+		var validationErr *ValidationError
+		if _, err := boards.Create(name); !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError for name %q, got %v", name, err)
+		}
+	}
+
+	found, err := boards.Get("WORK")
+	if err != nil || found.ID != work.ID {
+		t.Errorf("Expected to find board by name ignoring case, got %v (%v)", found, err)
+	}
+	if found, err := boards.Get(""); err != nil || found.ID != models.DefaultBoardID {
+		t.Errorf("Expected empty ref to be the default board, got %v (%v)", found, err)
+	}
+	if _, err := boards.Get("nope"); !errors.Is(err, ErrBoardNotFound) {
+		t.Errorf("Expected ErrBoardNotFound, got %v", err)
+	}
+
+	if _, err := boards.Rename(work.ID, "Job"); err != nil {
+		t.Fatalf("Failed to rename board: %v", err)
+	}
+	if _, err := boards.Rename("job", "default"); err == nil {
+		t.Errorf("Expected an error renaming to an existing name")
+	}
+
+	list, err := boards.List()
+	if err != nil || len(list) != 2 || list[0].ID != models.DefaultBoardID || list[1].Name != "Job" {
+		t.Errorf("Expected [default Job], got %v (%v)", list, err)
+	}
+}
+
+// TestTaskService_Boards tests tasks default to a board and subtasks stay
+// on their parent's board
+func TestTaskService_Boards(t *testing.T) {
+	svc, db := newTestService(t)
+	work, err := NewBoardService(db).Create("work")
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+
+	task := mustCreate(t, svc, models.ColumnInbox, 0)
+	if task.BoardID != models.DefaultBoardID {
+		t.Errorf("Expected default board, got %q", task.BoardID)
+	}
+
+	parent := &models.Task{Title: "Parent", Priority: 3, BoardID: work.ID}
+	if err := svc.Create(parent); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	child := &models.Task{Title: "Child", Priority: 3, ParentID: &parent.ID}
+	if err := svc.Create(child); err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+	if child.BoardID != work.ID {
+		t.Errorf("Expected subtask on its parent's board, got %q", child.BoardID)
+	}
+
+	var validationErr *ValidationError
+	if _, err := svc.Update(task.ID, TaskPatch{ParentID: &parent.ID}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for a parent on another board, got %v", err)
+	}
+	if err := svc.Create(&models.Task{Title: "Lost", Priority: 3, BoardID: "missing"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for a missing board, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// documentFence delimits the TOML front matter of a task document
+const documentFence = "+++"
+
+// dueDateLayout is the format of the due date in a task document
+const dueDateLayout = "2006-01-02"
+
+// TaskDocument is the editable form of a task used by 'ontop edit': TOML
+// front matter holding the task fields, followed by the description.
+type TaskDocument struct {
+	Title       string   `toml:"title"`
+	Priority    int      `toml:"priority"`
+	Column      string   `toml:"column"`
+	Progress    int      `toml:"progress"`
+	Tags        []string `toml:"tags"`
+	Parent      string   `toml:"parent"` // Empty for top-level tasks
+	Due         string   `toml:"due"`    // YYYY-MM-DD, empty for none
+	Description string   `toml:"-"`
+}
+
+// NewTaskDocument builds the document for a task
+func NewTaskDocument(task *models.Task) *TaskDocument {
+	doc := &TaskDocument{
+		Title:       task.Title,
+		Priority:    task.Priority,
+		Column:      task.Column,
+		Progress:    task.Progress,
+		Tags:        task.Tags,
+		Description: task.Description,
+	}
+	if doc.Tags == nil {
+		doc.Tags = []string{}
+	}
+	if task.ParentID != nil {
+		doc.Parent = *task.ParentID
+	}
+	if task.DueAt != nil {
+		doc.Due = task.DueAt.Format(dueDateLayout)
+	}
+	return doc
+}
+
+// FormatTaskDocument renders a task as a document to edit
+func FormatTaskDocument(task *models.Task) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(documentFence + "\n")
+	fmt.Fprintf(&buf, "# Task %s (version %d)\n", task.ID, task.Version)
+	fmt.Fprintf(&buf, "# Columns: %s. Priority: 1 (highest) to 5.\n", strings.Join(models.ValidColumns(), ", "))
+	if err := toml.NewEncoder(&buf).Encode(NewTaskDocument(task)); err != nil {
+		return nil, fmt.Errorf("failed to encode task: %w", err)
+	}
+	buf.WriteString(documentFence + "\n")
+	if task.Description != "" {
+		buf.WriteString(task.Description + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseTaskDocument parses an edited document. Everything after the closing
+// fence is the description, with surrounding blank lines trimmed.
+func ParseTaskDocument(data []byte) (*TaskDocument, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimLeft(text, "\n")
+
+	if !strings.HasPrefix(text, documentFence+"\n") {
+		return nil, invalidf("document must start with a '%s' line", documentFence)
+	}
+	rest := text[len(documentFence)+1:]
+
+	end := strings.Index(rest, "\n"+documentFence)
+	var frontMatter, body string
+	switch {
+	case strings.HasPrefix(rest, documentFence):
+		body = rest[len(documentFence):]
+	case end >= 0:
+		frontMatter = rest[:end+1]
+		body = rest[end+1+len(documentFence):]
+	default:
+		return nil, invalidf("missing closing '%s' line", documentFence)
+	}
+	if body != "" && !strings.HasPrefix(body, "\n") {
+		return nil, invalidf("closing '%s' must be on its own line", documentFence)
+	}
+
+	var doc TaskDocument
+	meta, err := toml.Decode(frontMatter, &doc)
+	if err != nil {
+		return nil, invalidf("invalid front matter: %v", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, invalidf("unknown field '%s'", undecoded[0])
+	}
+	if doc.Due != "" {
+		if _, err := time.ParseInLocation(dueDateLayout, doc.Due, time.Local); err != nil {
+			return nil, invalidf("invalid due date '%s', use YYYY-MM-DD", doc.Due)
+		}
+	}
+	doc.Description = strings.Trim(body, "\n")
+	return &doc, nil
+}
+
+// Patch returns a patch with only the fields that differ from task, so
+// edits to unrelated fields made elsewhere in the meantime are kept
+func (doc *TaskDocument) Patch(task *models.Task) TaskPatch {
+	var patch TaskPatch
+	orig := NewTaskDocument(task)
+
+	if doc.Title != orig.Title {
+		patch.Title = &doc.Title
+	}
+	if doc.Description != strings.Trim(orig.Description, "\n") {
+		patch.Description = &doc.Description
+	}
+	if doc.Priority != orig.Priority {
+		patch.Priority = &doc.Priority
+	}
+	if doc.Column != orig.Column {
+		patch.Column = &doc.Column
+	}
+	if doc.Progress != orig.Progress {
+		patch.Progress = &doc.Progress
+	}
+	if !sameTags(doc.Tags, orig.Tags) {
+		tags := doc.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		patch.Tags = &tags
+	}
+	if doc.Parent != orig.Parent {
+		patch.ParentID = &doc.Parent
+	}
+	if doc.Due != orig.Due {
+		dueAt, _ := time.ParseInLocation(dueDateLayout, doc.Due, time.Local) // Zero removes it
+		patch.DueAt = &dueAt
+	}
+	return patch
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p TaskPatch) IsEmpty() bool {
+	return p.Title == nil && p.Description == nil && p.Priority == nil &&
+		p.Column == nil && p.Progress == nil && p.Archived == nil &&
+		p.ParentID == nil && p.Tags == nil && len(p.AddTags) == 0 && len(p.RemoveTags) == 0 &&
+		p.DueAt == nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestTaskDocument_RoundTrip tests an unedited document yields an empty patch
+func TestTaskDocument_RoundTrip(t *testing.T) {
+	parentID := "01K98X44S6TZC6EREHC2RK0JGJ"
+	task := &models.Task{
+		ID:          "01K992C1WG3BVF7BB8KT7HJNWK",
+		Title:       `Fix "quoted" bug`,
+		Description: "First line\n\n+++ not a fence\nLast line",
+		Priority:    2,
+		Column:      models.ColumnInProgress,
+		Progress:    40,
+		ParentID:    &parentID,
+		Tags:        []string{"bug", "ui"},
+		Version:     3,
+	}
+
+	data, err := FormatTaskDocument(task)
+	if err != nil {
+		t.Fatalf("Failed to format document: %v", err)
+	}
+	doc, err := ParseTaskDocument(data)
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v\n%s", err, data)
+	}
+	if patch := doc.Patch(task); !patch.IsEmpty() {
+		t.Errorf("Expected empty patch, got %+v", patch)
+	}
+}
+
+// TestTaskDocument_Patch tests only edited fields end up in the patch
+func TestTaskDocument_Patch(t *testing.T) {
+	task := &models.Task{ID: "A", Title: "Old", Priority: 3, Column: models.ColumnInbox, Tags: []string{"bug"}}
+
+	edited := `+++
+title = "New"
+priority = 3
+column = "done"
+progress = 0
+tags = []
+parent = "B"
++++
+
+Now with a description
+`
+	doc, err := ParseTaskDocument([]byte(edited))
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+	patch := doc.Patch(task)
+
+	if patch.Title == nil || *patch.Title != "New" {
+		t.Errorf("Expected title patch, got %v", patch.Title)
+	}
+	if patch.Column == nil || *patch.Column != models.ColumnDone {
+		t.Errorf("Expected column patch, got %v", patch.Column)
+	}
+	if patch.Description == nil || *patch.Description != "Now with a description" {
+		t.Errorf("Expected description patch, got %v", patch.Description)
+	}
+	if patch.Tags == nil || len(*patch.Tags) != 0 {
+		t.Errorf("Expected tags to be cleared, got %v", patch.Tags)
+	}
+	if patch.ParentID == nil || *patch.ParentID != "B" {
+		t.Errorf("Expected parent patch, got %v", patch.ParentID)
+	}
+	if patch.Priority != nil || patch.Progress != nil {
+		t.Errorf("Expected unchanged priority and progress to be left out")
+	}
+}
+
+// TestParseTaskDocument_Errors tests malformed documents are rejected
+func TestParseTaskDocument_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"no front matter", "just text\n", "must start"},
+		{"unclosed", "+++\ntitle = \"x\"\n", "closing"},
+		{"bad toml", "+++\ntitle = \n+++\n", "invalid front matter"},
+		{"unknown field", "+++\nowner = \"me\"\n+++\n", "unknown field 'owner'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTaskDocument([]byte(tt.input))
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err)
+			}
+		})
+	}
+}
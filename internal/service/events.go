@@ -0,0 +1,76 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// Event types emitted when tasks change
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventMoved   = "moved"
+	EventDeleted = "deleted"
+)
+
+// TaskEvent describes a single change to a task between two snapshots
+type TaskEvent struct {
+	Type       string       `json:"type"`
+	Task       *models.Task `json:"task"`                  // Latest known state (last seen state for deletes)
+	FromColumn string       `json:"from_column,omitempty"` // Set for moved events
+}
+
+// SnapshotTasks indexes tasks by ID for use with DiffTasks
+func SnapshotTasks(tasks []*models.Task) map[string]*models.Task {
+	snapshot := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		snapshot[task.ID] = task
+	}
+	return snapshot
+}
+
+// DiffTasks compares two snapshots and returns the events that turn before
+// into after. Column changes are reported as moved; any other field change
+// is reported as updated. Events are ordered by task ID.
+func DiffTasks(before, after map[string]*models.Task) []TaskEvent {
+	var events []TaskEvent
+
+	for id, task := range after {
+		old, existed := before[id]
+		switch {
+		case !existed:
+			events = append(events, TaskEvent{Type: EventCreated, Task: task})
+		case old.Column != task.Column:
+			events = append(events, TaskEvent{Type: EventMoved, Task: task, FromColumn: old.Column})
+		case taskChanged(old, task):
+			events = append(events, TaskEvent{Type: EventUpdated, Task: task})
+		}
+	}
+
+	for id, task := range before {
+		if _, exists := after[id]; !exists {
+			events = append(events, TaskEvent{Type: EventDeleted, Task: task})
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Task.ID < events[j].Task.ID
+	})
+	return events
+}
+
+// taskChanged compares every mutable field, since updated_at only has
+// second precision and can miss edits made within the same second
+func taskChanged(a, b *models.Task) bool {
+	if a.Version != b.Version ||
+		!a.UpdatedAt.Equal(b.UpdatedAt) ||
+		a.Title != b.Title ||
+		a.Description != b.Description ||
+		a.Priority != b.Priority ||
+		a.Progress != b.Progress ||
+		a.Archived != b.Archived {
+		return true
+	}
+	return !sameParent(a.ParentID, b.ParentID) || !sameTags(a.Tags, b.Tags) || !sameTime(a.DueAt, b.DueAt)
+}
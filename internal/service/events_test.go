@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestDiffTasks_NoChanges tests identical snapshots produce no events
+func TestDiffTasks_NoChanges(t *testing.T) {
+	task := makeTask("A", "Task A", 1, nil)
+	before := SnapshotTasks([]*models.Task{task})
+	after := SnapshotTasks([]*models.Task{task})
+
+	if events := DiffTasks(before, after); len(events) != 0 {
+		t.Errorf("Expected no events, got %d", len(events))
+	}
+}
+
+// TestDiffTasks_EventTypes tests created, updated, moved and deleted detection
+func TestDiffTasks_EventTypes(t *testing.T) {
+	updated := makeTask("B", "Task B", 1, nil)
+	moved := makeTask("C", "Task C", 1, nil)
+	deleted := makeTask("D", "Task D", 1, nil)
+	before := SnapshotTasks([]*models.Task{updated, moved, deleted})
+
+	updatedAfter := *updated
+	updatedAfter.Title = "Task B renamed"
+	movedAfter := *moved
+	movedAfter.Column = models.ColumnDone
+	created := makeTask("A", "Task A", 1, nil)
+	after := SnapshotTasks([]*models.Task{created, &updatedAfter, &movedAfter})
+
+	events := DiffTasks(before, after)
+
+	expected := []struct {
+		id        string
+		eventType string
+	}{
+		{"A", EventCreated},
+		{"B", EventUpdated},
+		{"C", EventMoved},
+		{"D", EventDeleted},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, want := range expected {
+		if events[i].Task.ID != want.id || events[i].Type != want.eventType {
+			t.Errorf("Event %d: expected %s %s, got %s %s", i, want.eventType, want.id, events[i].Type, events[i].Task.ID)
+		}
+	}
+	if events[2].FromColumn != models.ColumnInbox {
+		t.Errorf("Expected moved event from inbox, got %q", events[2].FromColumn)
+	}
+}
+
+// TestDiffTasks_SameSecondEdit tests edits are detected even when updated_at is unchanged
+func TestDiffTasks_SameSecondEdit(t *testing.T) {
+	task := makeTask("A", "Task A", 1, nil)
+	task.UpdatedAt = time.Now().Truncate(time.Second)
+	edited := *task
+	edited.Tags = []string{"urgent"}
+
+	events := DiffTasks(SnapshotTasks([]*models.Task{task}), SnapshotTasks([]*models.Task{&edited}))
+	if len(events) != 1 || events[0].Type != EventUpdated {
+		t.Fatalf("Expected one updated event, got %+v", events)
+	}
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// Filter selects tasks matching an expression like
+// `tag:bug priority:<=2 -tag:wontfix "login"`. A task matches when it
+// matches every term.
+type Filter struct {
+	terms []filterTerm
+}
+
+// filterTerm is one term of a filter expression
+type filterTerm struct {
+	negate bool
+	match  func(*models.Task) bool
+}
+
+// filterToken is a word of a filter expression
+type filterToken struct {
+	text   string
+	quoted bool // Written in quotes: always text to search for
+	negate bool // Starts with - outside quotes
+}
+
+// ParseFilter parses a filter expression. Terms are separated by spaces:
+//
+//	tag:name       has the tag
+//	priority:N     has priority N; also <N, <=N, >N and >=N
+//	column:name    is in the column (inbox, in_progress, done)
+//	due:when       is due that day (see ParseDueDate); also <, <=, >, >=,
+//	               and due:none for tasks without a due date
+//	word, "text"   title or description contains the text, ignoring case
+//
+// Prefix a term with - to exclude the tasks it matches. An empty expression
+// matches every task.
+func ParseFilter(expr string, now time.Time) (*Filter, error) {
+	tokens, err := splitFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var f Filter
+	for _, token := range tokens {
+		match, err := parseFilterTerm(token, now)
+		if err != nil {
+			return nil, err
+		}
+		f.terms = append(f.terms, filterTerm{negate: token.negate, match: match})
+	}
+	return &f, nil
+}
+
+// Match reports whether task matches every term of the filter
+func (f *Filter) Match(task *models.Task) bool {
+	for _, term := range f.terms {
+		if term.match(task) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the tasks matching the filter, in order
+func (f *Filter) Apply(tasks []*models.Task) []*models.Task {
+	if f.IsEmpty() {
+		return tasks
+	}
+	var matched []*models.Task
+	for _, task := range tasks {
+		if f.Match(task) {
+			matched = append(matched, task)
+		}
+	}
+	return matched
+}
+
+// IsEmpty reports whether the filter has no terms, matching every task
+func (f *Filter) IsEmpty() bool {
+	return f == nil || len(f.terms) == 0
+}
+
+// splitFilter splits a filter expression into tokens at spaces outside
+// double quotes
+func splitFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	var token filterToken
+	var text strings.Builder
+	inToken, inQuotes := false, false
+
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			token.quoted = true
+			inToken = true
+		case r == ' ' && !inQuotes:
+			if inToken {
+				token.text = text.String()
+				tokens = append(tokens, token)
+			}
+			token, inToken = filterToken{}, false
+			text.Reset()
+		case r == '-' && !inQuotes && !inToken:
+			token.negate = true
+			inToken = true
+		default:
+			text.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inQuotes {
+		return nil, invalidf("unterminated quote in filter")
+	}
+	if inToken {
+		token.text = text.String()
+		tokens = append(tokens, token)
+	}
+
+	for _, token := range tokens {
+		if token.text == "" && !token.quoted {
+			return nil, invalidf("empty term in filter, put - right before a term to exclude it")
+		}
+	}
+	return tokens, nil
+}
+
+// parseFilterTerm returns the function matching the tasks a token selects
+func parseFilterTerm(token filterToken, now time.Time) (func(*models.Task) bool, error) {
+	key, value, ok := strings.Cut(token.text, ":")
+	if token.quoted || !ok {
+		text := strings.ToLower(token.text)
+		return func(task *models.Task) bool {
+			return strings.Contains(strings.ToLower(task.Title), text) ||
+				strings.Contains(strings.ToLower(task.Description), text)
+		}, nil
+	}
+
+	switch strings.ToLower(key) {
+	case "tag":
+		if value == "" {
+			return nil, invalidf("tag: needs a tag, like tag:bug")
+		}
+		return func(task *models.Task) bool {
+			for _, tag := range task.Tags {
+				if strings.EqualFold(tag, value) {
+					return true
+				}
+			}
+			return false
+		}, nil
+
+	case "priority":
+		op, number := splitComparison(value)
+		priority, err := strconv.Atoi(number)
+		if err != nil || priority < 1 || priority > 5 {
+			return nil, invalidf("invalid priority '%s', use a priority from 1 to 5, like priority:<=2", value)
+		}
+		return func(task *models.Task) bool {
+			return compare(op, task.Priority-priority)
+		}, nil
+
+	case "column":
+		column := strings.ReplaceAll(strings.ToLower(value), "-", "_")
+		if !models.IsValidColumn(column) {
+			return nil, invalidf("unknown column '%s'. Valid columns: %s", value, strings.Join(models.ValidColumns(), ", "))
+		}
+		return func(task *models.Task) bool {
+			return task.Column == column
+		}, nil
+
+	case "due":
+		if strings.EqualFold(value, "none") {
+			return func(task *models.Task) bool { return task.DueAt == nil }, nil
+		}
+		op, when := splitComparison(value)
+		due, err := ParseDueDate(when, now)
+		if err != nil {
+			return nil, err
+		}
+		return func(task *models.Task) bool {
+			return task.DueAt != nil && compare(op, task.DueAt.Compare(due))
+		}, nil
+	}
+
+	return nil, invalidf("unknown filter '%s:', use tag:, priority:, column: or due:, or quote the text to search for it", key)
+}
+
+// splitComparison splits a comparison operator (<, <=, >, >= or =) from the
+// start of a filter value. Without one, the operator is =.
+func splitComparison(value string) (op, rest string) {
+	for _, op := range []string{"<=", ">=", "<", ">", "="} {
+		if strings.HasPrefix(value, op) {
+			return op, value[len(op):]
+		}
+	}
+	return "=", value
+}
+
+// compare applies a comparison operator to the result of comparing two
+// values: negative, zero or positive
+func compare(op string, result int) bool {
+	switch op {
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	default:
+		return result == 0
+	}
+}
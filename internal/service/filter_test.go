@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestParseFilter tests each kind of term selects the expected tasks
+func TestParseFilter(t *testing.T) {
+	now := time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC) // A Tuesday
+	friday := time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC)
+
+	login := &models.Task{ID: "login", Title: "Fix login bug", Priority: 1, Column: models.ColumnInbox, Tags: []string{"bug"}, DueAt: &friday}
+	wontfix := &models.Task{ID: "wontfix", Title: "Login flicker", Priority: 2, Column: models.ColumnInbox, Tags: []string{"bug", "wontfix"}}
+	docs := &models.Task{ID: "docs", Title: "Write docs", Description: "Cover the login page", Priority: 3, Column: models.ColumnDone, Tags: []string{"docs"}}
+	tasks := []*models.Task{login, wontfix, docs}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", []string{"login", "wontfix", "docs"}},
+		{"tag:bug", []string{"login", "wontfix"}},
+		{"tag:BUG -tag:wontfix", []string{"login"}},
+		{"priority:<=2", []string{"login", "wontfix"}},
+		{"priority:>1", []string{"wontfix", "docs"}},
+		{"priority:3", []string{"docs"}},
+		{"column:done", []string{"docs"}},
+		{"column:in-progress", nil},
+		{"login", []string{"login", "wontfix", "docs"}},
+		{`"login bug"`, []string{"login"}},
+		{`-"login bug" login`, []string{"wontfix", "docs"}},
+		{`"tag:bug"`, nil},
+		{"due:fri", []string{"login"}},
+		{"due:<tue", nil},
+		{"due:none", []string{"wontfix", "docs"}},
+		{`tag:bug priority:<=2 -tag:wontfix "login"`, []string{"login"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter(tt.expr, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got []string
+			for _, task := range f.Apply(tasks) {
+				got = append(got, task.ID)
+			}
+			if !sameTags(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestParseFilter_Errors tests invalid expressions are rejected
+func TestParseFilter_Errors(t *testing.T) {
+	now := time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC)
+
+	for _, expr := range []string{`"login`, "priority:9", "priority:<=high", "column:someday", "due:never", "tag:", "owner:me", "bug - docs"} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFilter(expr, now)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// MergeTaskEdits performs a three-way merge after an update conflict.
+// base is the task as originally loaded, mine is the local edit and theirs
+// is the current stored version. Fields changed locally take mine; all
+// other fields take theirs. The result carries theirs' version so it can
+// be saved over the current row.
+func MergeTaskEdits(base, mine, theirs *models.Task) *models.Task {
+	merged := *theirs
+
+	if mine.Title != base.Title {
+		merged.Title = mine.Title
+	}
+	if mine.Description != base.Description {
+		merged.Description = mine.Description
+	}
+	if mine.Priority != base.Priority {
+		merged.Priority = mine.Priority
+	}
+	if mine.Column != base.Column {
+		merged.Column = mine.Column
+		merged.CompletedAt = mine.CompletedAt
+	}
+	if mine.Progress != base.Progress {
+		merged.Progress = mine.Progress
+	}
+	if mine.Archived != base.Archived {
+		merged.Archived = mine.Archived
+	}
+	if !sameParent(mine.ParentID, base.ParentID) {
+		merged.ParentID = mine.ParentID
+	}
+	if !sameTags(mine.Tags, base.Tags) {
+		merged.Tags = append([]string{}, mine.Tags...)
+	}
+	if !sameTime(mine.DueAt, base.DueAt) {
+		merged.DueAt = mine.DueAt
+	}
+
+	return &merged
+}
+
+// Helper functions
+
+func sameParent(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package service
+
+import "testing"
+
+// TestMergeTaskEdits_KeepsBothSides tests non-overlapping edits are combined
+func TestMergeTaskEdits_KeepsBothSides(t *testing.T) {
+	base := makeTask("A", "Original", 3, nil)
+	base.Version = 1
+
+	mine := *base
+	mine.Title = "My title"
+
+	theirs := *base
+	theirs.Priority = 1
+	theirs.Tags = []string{"urgent"}
+	theirs.Version = 2
+
+	merged := MergeTaskEdits(base, &mine, &theirs)
+
+	if merged.Title != "My title" {
+		t.Errorf("Expected local title, got %q", merged.Title)
+	}
+	if merged.Priority != 1 {
+		t.Errorf("Expected their priority 1, got %d", merged.Priority)
+	}
+	if len(merged.Tags) != 1 || merged.Tags[0] != "urgent" {
+		t.Errorf("Expected their tags, got %v", merged.Tags)
+	}
+	if merged.Version != 2 {
+		t.Errorf("Expected their version 2, got %d", merged.Version)
+	}
+}
+
+// TestMergeTaskEdits_LocalWinsOnOverlap tests fields edited on both sides take the local value
+func TestMergeTaskEdits_LocalWinsOnOverlap(t *testing.T) {
+	base := makeTask("A", "Original", 3, nil)
+
+	mine := *base
+	mine.Priority = 2
+	mine.ParentID = ptr("P1")
+
+	theirs := *base
+	theirs.Priority = 5
+
+	merged := MergeTaskEdits(base, &mine, &theirs)
+
+	if merged.Priority != 2 {
+		t.Errorf("Expected local priority 2, got %d", merged.Priority)
+	}
+	if merged.ParentID == nil || *merged.ParentID != "P1" {
+		t.Errorf("Expected local parent P1, got %v", merged.ParentID)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// QuickAdd is a task parsed from a one-line quick-capture string like
+// "Fix login bug #bug !1 ^01K99 due:fri @in_progress"
+type QuickAdd struct {
+	Title     string
+	Tags      []string
+	Priority  int        // 0 if not given
+	ParentRef string     // Unresolved parent reference, for a Resolver
+	Column    string     // Empty if not given
+	DueAt     *time.Time // Midnight local time of the due date
+}
+
+// ParseQuickAdd parses the inline tokens of a quick-capture string:
+//
+//	#tag          add a tag
+//	!1 .. !5      set priority
+//	^ref          make it a subtask of ref (ID prefix, #N, @last, ...)
+//	due:when      set the due date (see ParseDueDate)
+//	@column       place it in a column (inbox, in_progress, done)
+//
+// Everything else is the title. Prefix a word with a backslash to keep it in
+// the title as-is, e.g. \#1.
+func ParseQuickAdd(input string, now time.Time) (*QuickAdd, error) {
+	var qa QuickAdd
+	var words []string
+
+	for _, token := range strings.Fields(input) {
+		switch {
+		case strings.HasPrefix(token, `\`) && len(token) > 1:
+			words = append(words, token[1:])
+
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			qa.Tags = append(qa.Tags, token[1:])
+
+		case strings.HasPrefix(token, "!") && len(token) > 1:
+			priority, err := strconv.Atoi(token[1:])
+			if err != nil || priority < 1 || priority > 5 {
+				return nil, invalidf("invalid priority '%s', use !1 to !5", token)
+			}
+			qa.Priority = priority
+
+		case strings.HasPrefix(token, "^") && len(token) > 1:
+			qa.ParentRef = token[1:]
+
+		case strings.HasPrefix(strings.ToLower(token), "due:"):
+			due, err := ParseDueDate(token[len("due:"):], now)
+			if err != nil {
+				return nil, err
+			}
+			qa.DueAt = &due
+
+		case strings.HasPrefix(token, "@") && len(token) > 1:
+			column := strings.ReplaceAll(strings.ToLower(token[1:]), "-", "_")
+			if !models.IsValidColumn(column) {
+				return nil, invalidf("unknown column '%s'. Valid columns: %s", token[1:], strings.Join(models.ValidColumns(), ", "))
+			}
+			qa.Column = column
+
+		default:
+			words = append(words, token)
+		}
+	}
+
+	qa.Title = strings.Join(words, " ")
+	if qa.Title == "" {
+		return nil, invalidf("title is required")
+	}
+	return &qa, nil
+}
+
+// ParseDueDate parses a due date relative to now. It accepts:
+//
+//	today, tomorrow (or tmr)
+//	a weekday, like fri or friday: the next one, or today if it matches
+//	a number of days or weeks from today, like 3d or 2w
+//	a date, like 2025-11-07
+//
+// The result is midnight local time of that day.
+func ParseDueDate(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow", "tmr":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			days := (int(day) - int(today.Weekday()) + 7) % 7
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+
+	if n := len(value); n > 1 && (value[n-1] == 'd' || value[n-1] == 'w') {
+		if count, err := strconv.Atoi(value[:n-1]); err == nil && count >= 0 {
+			if value[n-1] == 'w' {
+				count *= 7
+			}
+			return today.AddDate(0, 0, count), nil
+		}
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, invalidf("invalid due date '%s', use today, tomorrow, a weekday, 3d, 2w or YYYY-MM-DD", value)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestParseQuickAdd tests inline tokens are split from the title
+func TestParseQuickAdd(t *testing.T) {
+	now := time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC) // A Tuesday
+	friday := time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC)
+
+	qa, err := ParseQuickAdd(`Fix login bug #bug #urgent !1 ^01K99 due:fri @in_progress \#42`, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if qa.Title != "Fix login bug #42" {
+		t.Errorf("Expected title 'Fix login bug #42', got %q", qa.Title)
+	}
+	if !sameTags(qa.Tags, []string{"bug", "urgent"}) {
+		t.Errorf("Expected tags [bug urgent], got %v", qa.Tags)
+	}
+	if qa.Priority != 1 {
+		t.Errorf("Expected priority 1, got %d", qa.Priority)
+	}
+	if qa.ParentRef != "01K99" {
+		t.Errorf("Expected parent ref 01K99, got %q", qa.ParentRef)
+	}
+	if qa.Column != models.ColumnInProgress {
+		t.Errorf("Expected column in_progress, got %q", qa.Column)
+	}
+	if qa.DueAt == nil || !qa.DueAt.Equal(friday) {
+		t.Errorf("Expected due %v, got %v", friday, qa.DueAt)
+	}
+}
+
+// TestParseQuickAdd_Errors tests invalid tokens are rejected
+func TestParseQuickAdd_Errors(t *testing.T) {
+	now := time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC)
+
+	for _, input := range []string{"", "#only #tags", "Task !9", "Task @someday", "Task due:never"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseQuickAdd(input, now)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestParseDueDate tests relative and absolute due dates
+func TestParseDueDate(t *testing.T) {
+	now := time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC) // A Tuesday
+	day := func(d int) time.Time { return time.Date(2025, 11, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"today", day(4)},
+		{"tomorrow", day(5)},
+		{"tmr", day(5)},
+		{"tue", day(4)},
+		{"Monday", day(10)},
+		{"fri", day(7)},
+		{"3d", day(7)},
+		{"1w", day(11)},
+		{"2025-11-20", day(20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDueDate(tt.value, now)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// Special task references
+const (
+	RefLast     = "@last"
+	RefSelected = "@selected"
+)
+
+// AmbiguousRefError is returned when an ID prefix matches more than one task
+type AmbiguousRefError struct {
+	Ref        string
+	Candidates []*models.Task
+}
+
+func (e *AmbiguousRefError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ambiguous task reference '%s' matches %d tasks:", e.Ref, len(e.Candidates))
+	for _, task := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %s", task.ID, task.Title)
+	}
+	return b.String()
+}
+
+// Resolver turns user-supplied task references into task IDs. A reference
+// is one of:
+//   - a full task ID or any unique prefix of one (case-insensitive)
+//   - a numeric alias printed by 'ontop list', like #3
+//   - @last, the most recently created or modified task
+//   - @selected, the task selected in the TUI
+type Resolver struct {
+	db       storage.DBTX
+	Aliases  []string // Task IDs by alias: #1 is Aliases[0]
+	Last     string   // Task ID for @last
+	Selected string   // Task ID for @selected
+}
+
+// NewResolver creates a Resolver that looks up ID prefixes in db
+func NewResolver(db storage.DBTX) *Resolver {
+	return &Resolver{db: db}
+}
+
+// Resolve returns the task ID for ref. Unknown references return an error
+// wrapping ErrTaskNotFound; ambiguous prefixes return *AmbiguousRefError.
+func (r *Resolver) Resolve(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+
+	switch {
+	case ref == "":
+		return "", invalidf("task reference is empty")
+
+	case strings.EqualFold(ref, RefLast):
+		if r.Last == "" {
+			return "", fmt.Errorf("%w: no task for %s yet", ErrTaskNotFound, RefLast)
+		}
+		return r.verify(r.Last, ref)
+
+	case strings.EqualFold(ref, RefSelected):
+		if r.Selected == "" {
+			return "", fmt.Errorf("%w: no task selected in the TUI", ErrTaskNotFound)
+		}
+		return r.verify(r.Selected, ref)
+
+	case strings.HasPrefix(ref, "#"):
+		n, err := strconv.Atoi(ref[1:])
+		if err != nil || n < 1 {
+			return "", invalidf("invalid alias '%s', expected a number like #3", ref)
+		}
+		if n > len(r.Aliases) {
+			return "", fmt.Errorf("%w: no alias %s, run 'ontop list' to refresh aliases", ErrTaskNotFound, ref)
+		}
+		return r.verify(r.Aliases[n-1], ref)
+	}
+
+	candidates, err := storage.FindTasksByIDPrefix(r.db, ref)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrTaskNotFound, ref)
+	case 1:
+		return candidates[0].ID, nil
+	}
+
+	// An exact match wins even if it is also a prefix of other IDs
+	for _, task := range candidates {
+		if strings.EqualFold(task.ID, ref) {
+			return task.ID, nil
+		}
+	}
+	return "", &AmbiguousRefError{Ref: ref, Candidates: candidates}
+}
+
+// verify checks that a remembered task ID still exists
+func (r *Resolver) verify(id, ref string) (string, error) {
+	if _, err := getTask(r.db, id); err != nil {
+		return "", fmt.Errorf("%s no longer refers to a task: %w", ref, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestResolver_Resolve tests every supported reference form
+func TestResolver_Resolve(t *testing.T) {
+	svc, db := newTestService(t)
+	for _, id := range []string{"01K9AAAA", "01K9AABB", "01K9BCCC", "01K9B"} {
+		task := &models.Task{ID: id, Title: "Task " + id, Priority: 3}
+		if err := svc.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	resolver := NewResolver(db)
+	resolver.Aliases = []string{"01K9AABB", "01K9BCCC"}
+	resolver.Last = "01K9AAAA"
+	resolver.Selected = "01K9BCCC"
+
+	tests := []struct {
+		ref    string
+		wantID string
+	}{
+		{"01K9AAAA", "01K9AAAA"},
+		{"01k9aaa", "01K9AAAA"},
+		{"01K9BC", "01K9BCCC"},
+		{"01K9B", "01K9B"}, // Exact match beats longer IDs sharing the prefix
+		{"#1", "01K9AABB"},
+		{"#2", "01K9BCCC"},
+		{"@last", "01K9AAAA"},
+		{"@selected", "01K9BCCC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			id, err := resolver.Resolve(tt.ref)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if id != tt.wantID {
+				t.Errorf("Expected %s, got %s", tt.wantID, id)
+			}
+		})
+	}
+}
+
+// TestResolver_Errors tests ambiguous, unknown and stale references
+func TestResolver_Errors(t *testing.T) {
+	svc, db := newTestService(t)
+	for _, id := range []string{"01K9AAAA", "01K9AABB"} {
+		if err := svc.Create(&models.Task{ID: id, Title: "Task", Priority: 3}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	resolver := NewResolver(db)
+	resolver.Aliases = []string{"GONE"}
+
+	var ambiguous *AmbiguousRefError
+	if _, err := resolver.Resolve("01K9AA"); !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousRefError, got %v", err)
+	}
+	if len(ambiguous.Candidates) != 2 {
+		t.Errorf("Expected 2 candidates, got %d", len(ambiguous.Candidates))
+	}
+
+	for _, ref := range []string{"ZZZ", "#1", "#2", "@last", "@selected"} {
+		if _, err := resolver.Resolve(ref); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound for %s, got %v", ref, err)
+		}
+	}
+
+	var validationErr *ValidationError
+	if _, err := resolver.Resolve("#abc"); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ValidationError for #abc, got %v", err)
+	}
+}
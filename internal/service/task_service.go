@@ -3,7 +3,9 @@ package service
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lucasefe/ontop/internal/models"
@@ -104,6 +106,473 @@ func UpdateTaskProgress(db *sql.DB, taskID string, progress int) error {
 	return nil
 }
 
+// ErrTaskNotFound is returned when a task does not exist or was deleted
+var ErrTaskNotFound = errors.New("task not found")
+
+// ValidationError reports invalid task input. Front-ends show the message
+// to the user as-is.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalidf(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+// TaskPatch describes a partial update to a task. Nil fields are left
+// unchanged.
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	Priority    *int
+	Column      *string
+	Progress    *int
+	Archived    *bool
+	ParentID    *string    // Empty string removes the parent
+	Tags        *[]string  // Replaces all tags
+	AddTags     []string   // Added after Tags is applied, skipping duplicates
+	RemoveTags  []string   // Removed after AddTags is applied
+	DueAt       *time.Time // The zero time removes the due date
+	Version     *int       // If set, the update fails with storage.ErrConflict unless it matches
+}
+
+// TaskService owns the task business rules shared by the CLI, TUI and API.
+// Every operation runs in a single database transaction and enforces:
+//   - CompletedAt is set when a task enters Done
+//   - CompletedAt is cleared when a task leaves Done
+//   - setting progress to 100% moves the task to Done
+//   - subtasks are limited to one level of nesting
+type TaskService struct {
+	db  *sql.DB
+	now func() time.Time // Clock, replaceable in tests
+}
+
+// NewTaskService creates a TaskService backed by db
+func NewTaskService(db *sql.DB) *TaskService {
+	return &TaskService{db: db, now: time.Now}
+}
+
+// Get returns a single task by ID
+func (s *TaskService) Get(id string) (*models.Task, error) {
+	return getTask(s.db, id)
+}
+
+// Create validates and inserts a new task. An empty ID is generated, an
+// empty column defaults to inbox and nil tags become an empty list.
+func (s *TaskService) Create(task *models.Task) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.createTx(tx, task)
+	})
+}
+
+// Move moves a task to another column
+func (s *TaskService) Move(id, column string) (*models.Task, error) {
+	return s.Update(id, TaskPatch{Column: &column})
+}
+
+// Archive archives or unarchives a task
+func (s *TaskService) Archive(id string, archived bool) (*models.Task, error) {
+	return s.Update(id, TaskPatch{Archived: &archived})
+}
+
+// Update applies a patch to a task and returns the updated task
+func (s *TaskService) Update(id string, patch TaskPatch) (*models.Task, error) {
+	var task *models.Task
+	err := s.withTx(func(tx *sql.Tx) error {
+		var err error
+		task, err = s.updateTx(tx, id, patch)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// Delete soft-deletes a task and its subtasks
+func (s *TaskService) Delete(id string) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		return s.deleteTx(tx, id)
+	})
+}
+
+// UpdateMany applies the same patch to every task in a single transaction.
+// If any update fails, none are applied.
+func (s *TaskService) UpdateMany(ids []string, patch TaskPatch) ([]*models.Task, error) {
+	tasks := make([]*models.Task, 0, len(ids))
+	err := s.withTx(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			task, err := s.updateTx(tx, id, patch)
+			if err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// DeleteMany deletes tasks and their subtasks in a single transaction and
+// returns how many tasks were deleted, including subtasks. If any task does
+// not exist, nothing is deleted.
+func (s *TaskService) DeleteMany(ids []string) (int, error) {
+	deleted := 0
+	err := s.withTx(func(tx *sql.Tx) error {
+		for _, id := range ids {
+			if _, err := getTask(tx, id); err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+		}
+
+		for _, id := range ids {
+			// Skip subtasks already deleted along with their parent
+			if _, err := getTask(tx, id); errors.Is(err, ErrTaskNotFound) {
+				continue
+			}
+			subtasks, err := storage.CountSubtasks(tx, id)
+			if err != nil {
+				return err
+			}
+			if err := s.deleteTx(tx, id); err != nil {
+				return err
+			}
+			deleted += 1 + subtasks
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// CreateWithSubtasks creates a task and its subtasks in a single
+// transaction. Subtasks are attached to the new task and default to its
+// column. If any task is invalid, none are created.
+func (s *TaskService) CreateWithSubtasks(parent *models.Task, subtasks []*models.Task) error {
+	return s.withTx(func(tx *sql.Tx) error {
+		if err := s.createTx(tx, parent); err != nil {
+			return err
+		}
+		for _, subtask := range subtasks {
+			subtask.ParentID = &parent.ID
+			if subtask.Column == "" {
+				subtask.Column = parent.Column
+			}
+			if err := s.createTx(tx, subtask); err != nil {
+				return fmt.Errorf("subtask '%s': %w", subtask.Title, err)
+			}
+		}
+		return nil
+	})
+}
+
+// withTx runs fn in a transaction, committing on success
+func (s *TaskService) withTx(fn func(tx *sql.Tx) error) error {
+	return withTx(s.db, fn)
+}
+
+func withTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback() // Ignore rollback error since we're already handling an error
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *TaskService) createTx(tx *sql.Tx, task *models.Task) error {
+	now := s.now()
+
+	task.Title = strings.TrimSpace(task.Title)
+	if task.Title == "" {
+		return invalidf("title is required")
+	}
+	if task.Priority < 1 || task.Priority > 5 {
+		return invalidf("priority must be between 1 and 5")
+	}
+	if task.Column == "" {
+		task.Column = models.ColumnInbox
+	}
+	if !models.IsValidColumn(task.Column) {
+		return invalidColumn(task.Column)
+	}
+	if task.Progress < 0 || task.Progress > 100 {
+		return invalidf("progress must be between 0 and 100")
+	}
+	if task.ParentID != nil && *task.ParentID == "" {
+		task.ParentID = nil
+	}
+	if task.ParentID != nil {
+		if err := validateParent(tx, task.ID, task.BoardID, *task.ParentID); err != nil {
+			return err
+		}
+	}
+	if err := setBoard(tx, task); err != nil {
+		return err
+	}
+	task.Tags = cleanTags(task.Tags)
+	if task.ID == "" {
+		task.ID = GenerateID()
+	}
+
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	task.CompletedAt = nil
+	task.DeletedAt = nil
+	setColumn(task, task.Column, now)
+	setProgress(task, task.Progress, now)
+
+	return storage.CreateTask(tx, task)
+}
+
+func (s *TaskService) updateTx(tx *sql.Tx, id string, patch TaskPatch) (*models.Task, error) {
+	now := s.now()
+
+	task, err := getTask(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Version != nil && *patch.Version != task.Version {
+		return nil, fmt.Errorf("%w: task %s", storage.ErrConflict, id)
+	}
+
+	if patch.Title != nil {
+		title := strings.TrimSpace(*patch.Title)
+		if title == "" {
+			return nil, invalidf("title cannot be empty")
+		}
+		task.Title = title
+	}
+
+	if patch.Description != nil {
+		task.Description = *patch.Description
+	}
+
+	if patch.Priority != nil {
+		if *patch.Priority < 1 || *patch.Priority > 5 {
+			return nil, invalidf("priority must be between 1 and 5")
+		}
+		task.Priority = *patch.Priority
+	}
+
+	if patch.Column != nil {
+		if !models.IsValidColumn(*patch.Column) {
+			return nil, invalidColumn(*patch.Column)
+		}
+		setColumn(task, *patch.Column, now)
+	}
+
+	if patch.Progress != nil {
+		if *patch.Progress < 0 || *patch.Progress > 100 {
+			return nil, invalidf("progress must be between 0 and 100")
+		}
+		setProgress(task, *patch.Progress, now)
+	}
+
+	if patch.Archived != nil {
+		task.Archived = *patch.Archived
+	}
+
+	if patch.ParentID != nil {
+		if *patch.ParentID == "" {
+			task.ParentID = nil
+		} else {
+			if err := validateParent(tx, task.ID, task.BoardID, *patch.ParentID); err != nil {
+				return nil, err
+			}
+			parentID := *patch.ParentID
+			task.ParentID = &parentID
+		}
+	}
+
+	if patch.Tags != nil {
+		task.Tags = cleanTags(*patch.Tags)
+	}
+	task.Tags = addTags(task.Tags, patch.AddTags)
+	task.Tags = removeTags(task.Tags, patch.RemoveTags)
+
+	if patch.DueAt != nil {
+		if patch.DueAt.IsZero() {
+			task.DueAt = nil
+		} else {
+			due := *patch.DueAt
+			task.DueAt = &due
+		}
+	}
+
+	task.UpdatedAt = now
+	if err := storage.UpdateTask(tx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (s *TaskService) deleteTx(tx *sql.Tx, id string) error {
+	if _, err := getTask(tx, id); err != nil {
+		return err
+	}
+	return storage.DeleteTask(tx, id)
+}
+
+// setColumn moves a task to column, keeping CompletedAt in sync
+func setColumn(task *models.Task, column string, now time.Time) {
+	task.Column = column
+
+	// Entering done sets completed_at; leaving it clears completed_at
+	if column == models.ColumnDone {
+		if task.CompletedAt == nil {
+			task.CompletedAt = &now
+		}
+	} else {
+		task.CompletedAt = nil
+	}
+}
+
+// setProgress updates progress, moving the task to done when it reaches 100%
+func setProgress(task *models.Task, progress int, now time.Time) {
+	task.Progress = progress
+	if progress == 100 && task.Column != models.ColumnDone {
+		setColumn(task, models.ColumnDone, now)
+	}
+}
+
+// validateParent ensures parentID can be the parent of taskID: it must
+// exist, be top-level, and taskID must not have subtasks of its own
+func validateParent(db storage.DBTX, taskID, boardID, parentID string) error {
+	if parentID == taskID {
+		return invalidf("a task cannot be its own parent")
+	}
+
+	parent, err := getTask(db, parentID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return invalidf("parent task not found: %s", parentID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if boardID != "" && parent.BoardID != boardID {
+		return invalidf("parent task is on another board")
+	}
+
+	// Prevent multi-level nesting (subtasks cannot have subtasks)
+	if parent.ParentID != nil {
+		return invalidf("cannot create subtask of subtask (max 1 level nesting)")
+	}
+
+	if taskID != "" {
+		count, err := storage.CountSubtasks(db, taskID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return invalidf("a task with subtasks cannot become a subtask (max 1 level nesting)")
+		}
+	}
+
+	return nil
+}
+
+// getTask wraps storage.GetTask, mapping missing rows to ErrTaskNotFound
+func getTask(db storage.DBTX, id string) (*models.Task, error) {
+	task, err := storage.GetTask(db, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, id)
+	}
+	return task, err
+}
+
+// setBoard defaults a new task to its parent's board, or else the default
+// board, and checks that the board exists
+func setBoard(db storage.DBTX, task *models.Task) error {
+	if task.BoardID == "" && task.ParentID != nil {
+		parent, err := getTask(db, *task.ParentID)
+		if err != nil {
+			return err
+		}
+		task.BoardID = parent.BoardID
+	}
+	if task.BoardID == "" {
+		task.BoardID = models.DefaultBoardID
+	}
+
+	_, err := storage.GetBoard(db, task.BoardID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalidf("board not found: %s", task.BoardID)
+	}
+	return err
+}
+
+func invalidColumn(column string) error {
+	return invalidf("invalid column '%s'. Valid columns: %s", column, strings.Join(models.ValidColumns(), ", "))
+}
+
+func cleanTags(tags []string) []string {
+	result := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func addTags(tags, add []string) []string {
+	for _, tag := range cleanTags(add) {
+		exists := false
+		for _, existing := range tags {
+			if existing == tag {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func removeTags(tags, remove []string) []string {
+	if len(remove) == 0 {
+		return tags
+	}
+	remove = cleanTags(remove)
+	result := []string{}
+	for _, tag := range tags {
+		keep := true
+		for _, r := range remove {
+			if tag == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
 // Helper function - duplicated from storage package to avoid circular dependency
 // TODO: Consider refactoring to avoid duplication
 type scanner interface {
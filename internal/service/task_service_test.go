@@ -0,0 +1,345 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// Helper to create a TaskService backed by a fresh database with a fixed clock
+func newTestService(t *testing.T) (*TaskService, *sql.DB) {
+	t.Helper()
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := storage.InitSchema(db); err != nil {
+		t.Fatalf("Failed to init schema: %v", err)
+	}
+
+	svc := NewTaskService(db)
+	svc.now = func() time.Time { return time.Date(2025, 11, 4, 14, 30, 0, 0, time.UTC) }
+	return svc, db
+}
+
+// Helper to create a task in the given column with the given progress
+func mustCreate(t *testing.T, svc *TaskService, column string, progress int) *models.Task {
+	t.Helper()
+	task := &models.Task{Title: "Task", Priority: 3, Column: column, Progress: progress}
+	if err := svc.Create(task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	return task
+}
+
+// TestTaskService_Create tests column and completion rules on create
+func TestTaskService_Create(t *testing.T) {
+	tests := []struct {
+		name          string
+		column        string
+		progress      int
+		wantColumn    string
+		wantCompleted bool
+	}{
+		{"defaults to inbox", "", 0, models.ColumnInbox, false},
+		{"in progress", models.ColumnInProgress, 50, models.ColumnInProgress, false},
+		{"created in done is completed", models.ColumnDone, 0, models.ColumnDone, true},
+		{"100% progress moves to done", models.ColumnInbox, 100, models.ColumnDone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService(t)
+			task := mustCreate(t, svc, tt.column, tt.progress)
+
+			stored, err := svc.Get(task.ID)
+			if err != nil {
+				t.Fatalf("Failed to get task: %v", err)
+			}
+			if stored.Column != tt.wantColumn {
+				t.Errorf("Expected column %s, got %s", tt.wantColumn, stored.Column)
+			}
+			if (stored.CompletedAt != nil) != tt.wantCompleted {
+				t.Errorf("Expected completed=%v, got CompletedAt=%v", tt.wantCompleted, stored.CompletedAt)
+			}
+		})
+	}
+}
+
+// TestTaskService_Update tests completion rules when moving and updating
+func TestTaskService_Update(t *testing.T) {
+	done := models.ColumnDone
+	inbox := models.ColumnInbox
+	inProgress := models.ColumnInProgress
+	fifty := 50
+	hundred := 100
+
+	tests := []struct {
+		name          string
+		startColumn   string
+		startProgress int
+		patch         TaskPatch
+		wantColumn    string
+		wantProgress  int
+		wantCompleted bool
+	}{
+		{"entering done sets completed_at", inbox, 0, TaskPatch{Column: &done}, done, 0, true},
+		{"leaving done clears completed_at", done, 0, TaskPatch{Column: &inProgress}, inProgress, 0, false},
+		{"moving within active columns", inbox, 0, TaskPatch{Column: &inProgress}, inProgress, 0, false},
+		{"100% progress auto-moves to done", inProgress, 50, TaskPatch{Progress: &hundred}, done, 100, true},
+		{"partial progress stays put", inbox, 0, TaskPatch{Progress: &fifty}, inbox, 50, false},
+		{"progress wins over column in same patch", inbox, 0, TaskPatch{Column: &inProgress, Progress: &hundred}, done, 100, true},
+		{"100% task can leave done", done, 100, TaskPatch{Column: &inbox}, inbox, 100, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService(t)
+			task := mustCreate(t, svc, tt.startColumn, tt.startProgress)
+
+			updated, err := svc.Update(task.ID, tt.patch)
+			if err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if updated.Column != tt.wantColumn {
+				t.Errorf("Expected column %s, got %s", tt.wantColumn, updated.Column)
+			}
+			if updated.Progress != tt.wantProgress {
+				t.Errorf("Expected progress %d, got %d", tt.wantProgress, updated.Progress)
+			}
+			if (updated.CompletedAt != nil) != tt.wantCompleted {
+				t.Errorf("Expected completed=%v, got CompletedAt=%v", tt.wantCompleted, updated.CompletedAt)
+			}
+
+			// The stored row must match what Update returned
+			stored, err := svc.Get(task.ID)
+			if err != nil {
+				t.Fatalf("Failed to get task: %v", err)
+			}
+			if stored.Column != updated.Column || (stored.CompletedAt != nil) != (updated.CompletedAt != nil) {
+				t.Errorf("Stored task does not match update result")
+			}
+		})
+	}
+}
+
+// TestTaskService_Validation tests invalid input is rejected with a ValidationError
+func TestTaskService_Validation(t *testing.T) {
+	bogus := "bogus"
+	zero := 0
+	tooMuch := 101
+	empty := "  "
+
+	tests := []struct {
+		name  string
+		patch TaskPatch
+	}{
+		{"invalid column", TaskPatch{Column: &bogus}},
+		{"priority too low", TaskPatch{Priority: &zero}},
+		{"progress too high", TaskPatch{Progress: &tooMuch}},
+		{"empty title", TaskPatch{Title: &empty}},
+		{"missing parent", TaskPatch{ParentID: &bogus}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService(t)
+			task := mustCreate(t, svc, models.ColumnInbox, 0)
+
+			_, err := svc.Update(task.ID, tt.patch)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestTaskService_Nesting tests subtasks are limited to one level
+func TestTaskService_Nesting(t *testing.T) {
+	svc, _ := newTestService(t)
+	parent := mustCreate(t, svc, models.ColumnInbox, 0)
+	child := &models.Task{Title: "Child", Priority: 3, ParentID: &parent.ID}
+	if err := svc.Create(child); err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		taskID   string
+		parentID string
+	}{
+		{"subtask of subtask", mustCreate(t, svc, models.ColumnInbox, 0).ID, child.ID},
+		{"parent with subtasks becomes subtask", parent.ID, mustCreate(t, svc, models.ColumnInbox, 0).ID},
+		{"own parent", parent.ID, parent.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parentID := tt.parentID
+			_, err := svc.Update(tt.taskID, TaskPatch{ParentID: &parentID})
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestTaskService_Tags tests tag replacement, addition and removal
+func TestTaskService_Tags(t *testing.T) {
+	svc, _ := newTestService(t)
+	task := mustCreate(t, svc, models.ColumnInbox, 0)
+
+	tags := []string{"bug", " urgent ", ""}
+	updated, err := svc.Update(task.ID, TaskPatch{Tags: &tags, AddTags: []string{"bug", "ui"}, RemoveTags: []string{"urgent"}})
+	if err != nil {
+		t.Fatalf("Failed to update tags: %v", err)
+	}
+
+	want := []string{"bug", "ui"}
+	if !sameTags(updated.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, updated.Tags)
+	}
+}
+
+// TestTaskService_VersionConflict tests stale versions are rejected
+func TestTaskService_VersionConflict(t *testing.T) {
+	svc, _ := newTestService(t)
+	task := mustCreate(t, svc, models.ColumnInbox, 0)
+	staleVersion := task.Version
+
+	if _, err := svc.Move(task.ID, models.ColumnInProgress); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+
+	title := "Stale edit"
+	_, err := svc.Update(task.ID, TaskPatch{Title: &title, Version: &staleVersion})
+	if !errors.Is(err, storage.ErrConflict) {
+		t.Errorf("Expected ErrConflict, got %v", err)
+	}
+}
+
+// TestTaskService_ArchiveAndDelete tests archive toggling and cascading delete
+func TestTaskService_ArchiveAndDelete(t *testing.T) {
+	svc, _ := newTestService(t)
+	parent := mustCreate(t, svc, models.ColumnInbox, 0)
+	child := &models.Task{Title: "Child", Priority: 3, ParentID: &parent.ID}
+	if err := svc.Create(child); err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+
+	archived, err := svc.Archive(parent.ID, true)
+	if err != nil || !archived.Archived {
+		t.Fatalf("Expected archived task, got %v (err %v)", archived, err)
+	}
+
+	if err := svc.Delete(parent.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	for _, id := range []string{parent.ID, child.ID} {
+		if _, err := svc.Get(id); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected %s to be deleted, got %v", id, err)
+		}
+	}
+
+	if err := svc.Delete(parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Expected ErrTaskNotFound deleting twice, got %v", err)
+	}
+}
+
+// TestTaskService_UpdateMany tests bulk updates are all-or-nothing
+func TestTaskService_UpdateMany(t *testing.T) {
+	svc, _ := newTestService(t)
+	first := mustCreate(t, svc, models.ColumnInbox, 0)
+	second := mustCreate(t, svc, models.ColumnInbox, 0)
+
+	done := models.ColumnDone
+	updated, err := svc.UpdateMany([]string{first.ID, second.ID}, TaskPatch{Column: &done, AddTags: []string{"q3"}})
+	if err != nil {
+		t.Fatalf("Failed to update tasks: %v", err)
+	}
+	for _, task := range updated {
+		if task.Column != done || task.CompletedAt == nil || !sameTags(task.Tags, []string{"q3"}) {
+			t.Errorf("Expected task %s done and tagged, got %+v", task.ID, task)
+		}
+	}
+
+	// A missing task rolls back the whole batch
+	inbox := models.ColumnInbox
+	if _, err := svc.UpdateMany([]string{first.ID, "missing"}, TaskPatch{Column: &inbox}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+	}
+	stored, err := svc.Get(first.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if stored.Column != done {
+		t.Errorf("Expected failed batch to be rolled back, got column %s", stored.Column)
+	}
+}
+
+// TestTaskService_DeleteMany tests bulk deletes count cascaded subtasks once
+func TestTaskService_DeleteMany(t *testing.T) {
+	svc, _ := newTestService(t)
+	parent := mustCreate(t, svc, models.ColumnInbox, 0)
+	child := &models.Task{Title: "Child", Priority: 3, ParentID: &parent.ID}
+	if err := svc.Create(child); err != nil {
+		t.Fatalf("Failed to create subtask: %v", err)
+	}
+	other := mustCreate(t, svc, models.ColumnInbox, 0)
+
+	if _, err := svc.DeleteMany([]string{other.ID, "missing"}); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("Expected ErrTaskNotFound, got %v", err)
+	}
+	if _, err := svc.Get(other.ID); err != nil {
+		t.Fatalf("Expected failed batch to be rolled back, got %v", err)
+	}
+
+	deleted, err := svc.DeleteMany([]string{parent.ID, child.ID, other.ID})
+	if err != nil {
+		t.Fatalf("Failed to delete tasks: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Expected 3 deleted tasks, got %d", deleted)
+	}
+}
+
+// TestTaskService_CreateWithSubtasks tests a task tree is created atomically
+func TestTaskService_CreateWithSubtasks(t *testing.T) {
+	svc, db := newTestService(t)
+
+	parent := &models.Task{Title: "Release", Priority: 2, Column: models.ColumnInProgress}
+	subtasks := []*models.Task{
+		{Title: "Tag", Priority: 3},
+		{Title: "Announce", Priority: 3, Column: models.ColumnInbox},
+	}
+	if err := svc.CreateWithSubtasks(parent, subtasks); err != nil {
+		t.Fatalf("Failed to create tasks: %v", err)
+	}
+	if subtasks[0].ParentID == nil || *subtasks[0].ParentID != parent.ID {
+		t.Errorf("Expected subtask to be attached to %s", parent.ID)
+	}
+	if subtasks[0].Column != models.ColumnInProgress || subtasks[1].Column != models.ColumnInbox {
+		t.Errorf("Expected subtask columns [in_progress inbox], got [%s %s]", subtasks[0].Column, subtasks[1].Column)
+	}
+
+	invalid := []*models.Task{{Title: "Fine", Priority: 3}, {Title: "Bad", Priority: 9}}
+	if err := svc.CreateWithSubtasks(&models.Task{Title: "Other", Priority: 3}, invalid); err == nil {
+		t.Fatalf("Expected an error for an invalid subtask")
+	}
+	count, err := storage.CountSubtasks(db, parent.ID)
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 subtasks, got %d (%v)", count, err)
+	}
+	tasks, err := storage.ListTasks(db, map[string]interface{}{"archived": false})
+	if err != nil || len(tasks) != 3 {
+		t.Errorf("Expected the failed tree to be rolled back, got %d tasks (%v)", len(tasks), err)
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// ErrTemplateNotFound is returned when no template has the requested name
+var ErrTemplateNotFound = errors.New("template not found")
+
+// templateVar matches a {{name}} variable in template titles and descriptions
+var templateVar = regexp.MustCompile(`{{\s*([A-Za-z0-9_-]+)\s*}}`)
+
+// templateName matches valid template names, which are also file names
+var templateName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Template is a reusable task tree: a parent task and its subtasks, stored
+// as <name>.toml in the templates directory. Titles and descriptions may
+// contain {{name}} variables that are filled in when it is used.
+type Template struct {
+	Title       string         `toml:"title"`
+	Description string         `toml:"description,omitempty"`
+	Priority    int            `toml:"priority,omitempty"` // 0 uses the default
+	Tags        []string       `toml:"tags,omitempty"`
+	Subtasks    []TemplateTask `toml:"subtasks,omitempty"`
+}
+
+// TemplateTask is a subtask in a Template
+type TemplateTask struct {
+	Title       string   `toml:"title"`
+	Description string   `toml:"description,omitempty"`
+	Priority    int      `toml:"priority,omitempty"` // 0 uses the default
+	Column      string   `toml:"column,omitempty"`   // Empty uses the parent's column
+	Tags        []string `toml:"tags,omitempty"`
+}
+
+// NewTemplate builds a template from a task and its subtasks. Columns and
+// progress are left out so new copies start fresh.
+func NewTemplate(task *models.Task, subtasks []*models.Task) *Template {
+	tmpl := &Template{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Tags:        task.Tags,
+	}
+	for _, subtask := range subtasks {
+		tmpl.Subtasks = append(tmpl.Subtasks, TemplateTask{
+			Title:       subtask.Title,
+			Description: subtask.Description,
+			Priority:    subtask.Priority,
+			Tags:        subtask.Tags,
+		})
+	}
+	return tmpl
+}
+
+// ParseTemplate parses and validates a template file
+func ParseTemplate(data []byte) (*Template, error) {
+	var tmpl Template
+	meta, err := toml.Decode(string(data), &tmpl)
+	if err != nil {
+		return nil, invalidf("invalid template: %v", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, invalidf("unknown field '%s'", undecoded[0])
+	}
+
+	if strings.TrimSpace(tmpl.Title) == "" {
+		return nil, invalidf("template title is required")
+	}
+	if tmpl.Priority < 0 || tmpl.Priority > 5 {
+		return nil, invalidf("priority must be between 1 and 5")
+	}
+	for i, subtask := range tmpl.Subtasks {
+		if strings.TrimSpace(subtask.Title) == "" {
+			return nil, invalidf("subtask %d: title is required", i+1)
+		}
+		if subtask.Priority < 0 || subtask.Priority > 5 {
+			return nil, invalidf("subtask %d: priority must be between 1 and 5", i+1)
+		}
+		if subtask.Column != "" && !models.IsValidColumn(subtask.Column) {
+			return nil, invalidColumn(subtask.Column)
+		}
+	}
+	return &tmpl, nil
+}
+
+// FormatTemplate renders a template as TOML
+func FormatTemplate(tmpl *Template) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(tmpl); err != nil {
+		return nil, fmt.Errorf("failed to encode template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Variables returns the names of the {{name}} variables used in the
+// template, in order of first use
+func (t *Template) Variables() []string {
+	var names []string
+	collect := func(text string) {
+		for _, match := range templateVar.FindAllStringSubmatch(text, -1) {
+			if !slices.Contains(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+
+	collect(t.Title)
+	collect(t.Description)
+	for _, subtask := range t.Subtasks {
+		collect(subtask.Title)
+		collect(subtask.Description)
+	}
+	return names
+}
+
+// NewTasks substitutes vars into the template and returns the tasks to
+// create, ready for TaskService.CreateWithSubtasks. Every variable must
+// have a value, and every value must be used.
+func (t *Template) NewTasks(vars map[string]string) (*models.Task, []*models.Task, error) {
+	used := t.Variables()
+	for _, name := range used {
+		if _, ok := vars[name]; !ok {
+			return nil, nil, invalidf("missing value for variable '%s'", name)
+		}
+	}
+	for name := range vars {
+		if !slices.Contains(used, name) {
+			return nil, nil, invalidf("template does not use variable '%s'", name)
+		}
+	}
+
+	expand := func(text string) string {
+		return templateVar.ReplaceAllStringFunc(text, func(match string) string {
+			return vars[templateVar.FindStringSubmatch(match)[1]]
+		})
+	}
+
+	parent := &models.Task{
+		Title:       expand(t.Title),
+		Description: expand(t.Description),
+		Priority:    defaultPriority(t.Priority),
+		Tags:        append([]string{}, t.Tags...),
+	}
+	var subtasks []*models.Task
+	for _, item := range t.Subtasks {
+		subtasks = append(subtasks, &models.Task{
+			Title:       expand(item.Title),
+			Description: expand(item.Description),
+			Priority:    defaultPriority(item.Priority),
+			Column:      item.Column,
+			Tags:        append([]string{}, item.Tags...),
+		})
+	}
+	return parent, subtasks, nil
+}
+
+// ListTemplates returns the names of the templates in dir, sorted
+func ListTemplates(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".toml")
+		if ok && !entry.IsDir() && templateName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadTemplate reads the named template from dir
+func LoadTemplate(dir, name string) (*Template, error) {
+	path, err := templatePath(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := ParseTemplate(data)
+	if err != nil {
+		return nil, invalidf("%s: %v", path, err)
+	}
+	return tmpl, nil
+}
+
+// SaveTemplate writes a template to dir as name. It fails if the template
+// already exists, unless overwrite is set.
+func SaveTemplate(dir, name string, tmpl *Template, overwrite bool) (string, error) {
+	path, err := templatePath(dir, name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return "", invalidf("template '%s' already exists", name)
+	}
+
+	data, err := FormatTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write template: %w", err)
+	}
+	return path, nil
+}
+
+// DeleteTemplate removes the named template from dir
+func DeleteTemplate(dir, name string) error {
+	path, err := templatePath(dir, name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	} else if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// Helper functions
+
+func templatePath(dir, name string) (string, error) {
+	if !templateName.MatchString(name) {
+		return "", invalidf("invalid template name '%s': use letters, digits, - and _", name)
+	}
+	return filepath.Join(dir, name+".toml"), nil
+}
+
+func defaultPriority(priority int) int {
+	if priority == 0 {
+		return 3
+	}
+	return priority
+}
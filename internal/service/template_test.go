@@ -0,0 +1,117 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+const releaseTemplate = `
+title = "Release {{version}}"
+description = "Ship {{ version }} to {{env}}"
+priority = 2
+tags = ["release"]
+
+[[subtasks]]
+title = "Tag v{{version}}"
+
+[[subtasks]]
+title = "Announce"
+priority = 4
+column = "done"
+`
+
+// TestTemplate_NewTasks tests variables are substituted and defaults applied
+func TestTemplate_NewTasks(t *testing.T) {
+	tmpl, err := ParseTemplate([]byte(releaseTemplate))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if vars := tmpl.Variables(); !sameTags(vars, []string{"version", "env"}) {
+		t.Errorf("Expected variables [version env], got %v", vars)
+	}
+
+	parent, subtasks, err := tmpl.NewTasks(map[string]string{"version": "1.4", "env": "prod"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if parent.Title != "Release 1.4" || parent.Description != "Ship 1.4 to prod" || parent.Priority != 2 {
+		t.Errorf("Unexpected parent: %+v", parent)
+	}
+	if len(subtasks) != 2 {
+		t.Fatalf("Expected 2 subtasks, got %d", len(subtasks))
+	}
+	if subtasks[0].Title != "Tag v1.4" || subtasks[0].Priority != 3 {
+		t.Errorf("Unexpected first subtask: %+v", subtasks[0])
+	}
+
+	for name, vars := range map[string]map[string]string{
+		"missing variable": {"version": "1.4"},
+		"unused variable":  {"version": "1.4", "env": "prod", "typo": "x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := tmpl.NewTasks(vars)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestParseTemplate_Errors tests invalid templates are rejected
+func TestParseTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing title", `priority = 1`},
+		{"bad priority", "title = \"A\"\npriority = 9"},
+		{"unknown field", "title = \"A\"\nowner = \"me\""},
+		{"bad subtask column", "title = \"A\"\n[[subtasks]]\ntitle = \"B\"\ncolumn = \"later\""},
+		{"invalid TOML", `title = `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTemplate([]byte(tt.input))
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+// TestTemplate_SaveLoad tests a template saved from a task tree loads back
+func TestTemplate_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	task := &models.Task{Title: "Release 1.3", Priority: 2, Column: models.ColumnDone, Tags: []string{"release"}}
+	subtasks := []*models.Task{{Title: "Tag", Priority: 3, Column: models.ColumnDone, Progress: 100}}
+
+	if _, err := SaveTemplate(dir, "release", NewTemplate(task, subtasks), false); err != nil {
+		t.Fatalf("Failed to save template: %v", err)
+	}
+	if _, err := SaveTemplate(dir, "release", NewTemplate(task, subtasks), false); err == nil {
+		t.Errorf("Expected an error saving over an existing template")
+	}
+
+	names, err := ListTemplates(dir)
+	if err != nil || len(names) != 1 || names[0] != "release" {
+		t.Fatalf("Expected [release], got %v (%v)", names, err)
+	}
+
+	loaded, err := LoadTemplate(dir, "release")
+	if err != nil {
+		t.Fatalf("Failed to load template: %v", err)
+	}
+	if loaded.Title != "Release 1.3" || len(loaded.Subtasks) != 1 || loaded.Subtasks[0].Column != "" {
+		t.Errorf("Unexpected template: %+v", loaded)
+	}
+
+	if _, err := LoadTemplate(dir, "missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound, got %v", err)
+	}
+}
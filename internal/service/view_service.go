@@ -0,0 +1,87 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// ErrViewNotFound is returned when no view has a name
+var ErrViewNotFound = errors.New("view not found")
+
+// maxViewName is the longest allowed view name
+const maxViewName = 50
+
+// ViewService owns the rules for views, named filters with a sort and layout
+// shared by the CLI and the TUI
+type ViewService struct {
+	db  *sql.DB
+	now func() time.Time // Clock, replaceable in tests
+}
+
+// NewViewService creates a ViewService backed by db
+func NewViewService(db *sql.DB) *ViewService {
+	return &ViewService{db: db, now: time.Now}
+}
+
+// List returns all views by name
+func (s *ViewService) List() ([]*models.View, error) {
+	return storage.ListViews(s.db)
+}
+
+// Get returns the view with the given name, ignoring case
+func (s *ViewService) Get(name string) (*models.View, error) {
+	view, err := storage.GetView(s.db, strings.TrimSpace(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrViewNotFound, name)
+	}
+	return view, err
+}
+
+// Save stores a view, replacing the view with the same name if it already
+// exists. Empty sort and layout keep the ones in use when the view is
+// applied.
+func (s *ViewService) Save(view *models.View) (*models.View, error) {
+	name := strings.TrimSpace(view.Name)
+	if name == "" {
+		return nil, invalidf("view name is required")
+	}
+	if len(name) > maxViewName {
+		return nil, invalidf("view name must be at most %d characters", maxViewName)
+	}
+	if strings.HasPrefix(name, "-") {
+		return nil, invalidf("view name cannot start with '-'")
+	}
+	filter := strings.TrimSpace(view.Filter)
+	if _, err := ParseFilter(filter, s.now()); err != nil {
+		return nil, err
+	}
+	if view.Sort != "" && !slices.Contains(config.SortModes, view.Sort) {
+		return nil, invalidf("invalid sort '%s'. Valid sorts: %s", view.Sort, strings.Join(config.SortModes, ", "))
+	}
+	if view.Layout != "" && view.Layout != "column" && view.Layout != "row" {
+		return nil, invalidf("invalid layout '%s', use column or row", view.Layout)
+	}
+
+	saved := &models.View{Name: name, Filter: filter, Sort: view.Sort, Layout: view.Layout, CreatedAt: s.now()}
+	if err := storage.SaveView(s.db, saved); err != nil {
+		return nil, err
+	}
+	return s.Get(name)
+}
+
+// Delete removes the view with the given name, ignoring case
+func (s *ViewService) Delete(name string) error {
+	err := storage.DeleteView(s.db, strings.TrimSpace(name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrViewNotFound, name)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// TestViewService tests saving, replacing, finding and deleting views
+func TestViewService(t *testing.T) {
+	_, db := newTestService(t)
+	views := NewViewService(db)
+
+	triage, err := views.Save(&models.View{Name: " Triage ", Filter: "tag:bug priority:<=2", Sort: "updated", Layout: "row"})
+	if err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	if triage.Name != "Triage" || triage.Filter != "tag:bug priority:<=2" || triage.Sort != "updated" || triage.Layout != "row" {
+		t.Errorf("Expected view Triage with its filter, sort and layout, got %+v", triage)
+	}
+
+	for _, view := range []*models.View{
+		{Name: "", Filter: "tag:bug"},
+		{Name: "-x", Filter: "tag:bug"},
+		{Name: "bad", Filter: "priority:9"},
+		{Name: "bad", Filter: "tag:bug", Sort: "due"},
+		{Name: "bad", Filter: "tag:bug", Layout: "grid"},
+	} {
+		var validationErr *ValidationError
+		if _, err := views.Save(view); !errors.As(err, &validationErr) {
+			t.Errorf("Expected ValidationError for view %+v, got %v", view, err)
+		}
+	}
+
+	// Saving under the same name, in any case, replaces the view
+	if _, err := views.Save(&models.View{Name: "triage", Filter: "tag:bug"}); err != nil {
+		t.Fatalf("Failed to replace view: %v", err)
+	}
+	found, err := views.Get("TRIAGE")
+	if err != nil || found.Filter != "tag:bug" || found.Sort != "" || found.Layout != "" {
+		t.Errorf("Expected replaced view with filter 'tag:bug' and no sort or layout, got %+v (%v)", found, err)
+	}
+	if _, err := views.Get("nope"); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Expected ErrViewNotFound, got %v", err)
+	}
+
+	if _, err := views.Save(&models.View{Name: "bugs", Filter: "tag:bug"}); err != nil {
+		t.Fatalf("Failed to save view: %v", err)
+	}
+	list, err := views.List()
+	if err != nil || len(list) != 2 || list[0].Name != "bugs" {
+		t.Errorf("Expected views [bugs Triage], got %v (%v)", list, err)
+	}
+
+	if err := views.Delete("BUGS"); err != nil {
+		t.Fatalf("Failed to delete view: %v", err)
+	}
+	if err := views.Delete("bugs"); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Expected ErrViewNotFound deleting a deleted view, got %v", err)
+	}
+	if list, err := views.List(); err != nil || len(list) != 1 {
+		t.Errorf("Expected 1 view left, got %v (%v)", list, err)
+	}
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// CreateBoard inserts a new board
+func CreateBoard(db DBTX, board *models.Board) error {
+	_, err := db.Exec(`INSERT INTO boards (id, name, created_at) VALUES (?, ?, ?)`,
+		board.ID, board.Name, board.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to insert board: %w", err)
+	}
+	return nil
+}
+
+// GetBoard retrieves a board by ID. Returns an error wrapping sql.ErrNoRows
+// if there is none.
+func GetBoard(db DBTX, id string) (*models.Board, error) {
+	return scanBoard(db.QueryRow(`SELECT id, name, created_at FROM boards WHERE id = ?`, id))
+}
+
+// GetBoardByName retrieves a board by name, ignoring case
+func GetBoardByName(db DBTX, name string) (*models.Board, error) {
+	return scanBoard(db.QueryRow(`SELECT id, name, created_at FROM boards WHERE name = ? COLLATE NOCASE`, name))
+}
+
+// ListBoards returns all boards, the default board first, then by name
+func ListBoards(db DBTX) ([]*models.Board, error) {
+	rows, err := db.Query(`
+		SELECT id, name, created_at FROM boards
+		ORDER BY id != ?, name COLLATE NOCASE
+	`, models.DefaultBoardID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query boards: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Best effort close
+	}()
+
+	var boards []*models.Board
+	for rows.Next() {
+		board, err := scanBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+	return boards, rows.Err()
+}
+
+// RenameBoard changes a board's name. Returns sql.ErrNoRows if the board
+// does not exist.
+func RenameBoard(db DBTX, id, name string) error {
+	result, err := db.Exec(`UPDATE boards SET name = ? WHERE id = ?`, name, id)
+	if err != nil {
+		return fmt.Errorf("failed to rename board: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountTasksByBoard returns the number of active (not archived or deleted)
+// tasks on each board, keyed by board ID
+func CountTasksByBoard(db DBTX) (map[string]int, error) {
+	rows, err := db.Query(`
+		SELECT board_id, COUNT(*) FROM tasks
+		WHERE deleted_at IS NULL AND archived = false
+		GROUP BY board_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Best effort close
+	}()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan count: %w", err)
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
+
+func scanBoard(s scanner) (*models.Board, error) {
+	var board models.Board
+	var createdAtStr string
+	if err := s.Scan(&board.ID, &board.Name, &createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to scan board: %w", err)
+	}
+
+	var err error
+	if board.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &board, nil
+}
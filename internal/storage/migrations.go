@@ -3,11 +3,26 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
 )
 
 // InitSchema creates the database schema if it doesn't exist
 func InitSchema(db *sql.DB) error {
 	schema := `
+	CREATE TABLE IF NOT EXISTS boards (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		created_at TEXT NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS views (
+		name TEXT PRIMARY KEY COLLATE NOCASE,
+		filter TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);
+
 	CREATE TABLE IF NOT EXISTS tasks (
 		id TEXT PRIMARY KEY,
 		title TEXT NOT NULL DEFAULT '',
@@ -22,7 +37,11 @@ func InitSchema(db *sql.DB) error {
 		updated_at TEXT NOT NULL,
 		completed_at TEXT,
 		deleted_at TEXT,
-		FOREIGN KEY (parent_id) REFERENCES tasks(id)
+		version INTEGER NOT NULL DEFAULT 1,
+		due_at TEXT,
+		board_id TEXT NOT NULL DEFAULT 'default',
+		FOREIGN KEY (parent_id) REFERENCES tasks(id),
+		FOREIGN KEY (board_id) REFERENCES boards(id)
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_tasks_column ON tasks(column);
@@ -44,6 +63,46 @@ func InitSchema(db *sql.DB) error {
 	`)
 	// Ignore error if column already exists
 
+	// Add version column for optimistic concurrency control
+	_, _ = db.Exec(`
+		ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+	`)
+	// Ignore error if column already exists
+
+	// Add due_at column for due dates
+	_, _ = db.Exec(`
+		ALTER TABLE tasks ADD COLUMN due_at TEXT;
+	`)
+	// Ignore error if column already exists
+
+	// Add board_id column; existing tasks go to the default board
+	_, _ = db.Exec(`
+		ALTER TABLE tasks ADD COLUMN board_id TEXT NOT NULL DEFAULT 'default';
+	`)
+	// Ignore error if column already exists
+
+	// Add sort and layout columns to views; existing views keep the current ones
+	_, _ = db.Exec(`
+		ALTER TABLE views ADD COLUMN sort TEXT NOT NULL DEFAULT '';
+	`)
+	_, _ = db.Exec(`
+		ALTER TABLE views ADD COLUMN layout TEXT NOT NULL DEFAULT '';
+	`)
+	// Ignore error if columns already exist
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_tasks_board_id ON tasks(board_id);`)
+	if err != nil {
+		return fmt.Errorf("failed to index boards: %w", err)
+	}
+
+	// Every database has a default board
+	_, err = db.Exec(`
+		INSERT OR IGNORE INTO boards (id, name, created_at) VALUES (?, 'default', ?);
+	`, models.DefaultBoardID, time.Now().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to create default board: %w", err)
+	}
+
 	// Migrate existing data: copy description to title if title is empty
 	_, err = db.Exec(`
 		UPDATE tasks SET title = substr(description, 1, 100) WHERE title = '' OR title IS NULL;
@@ -1,34 +1,68 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/lucasefe/ontop/internal/config"
 	"github.com/lucasefe/ontop/internal/models"
 	_ "modernc.org/sqlite"
 )
 
-// NewDB creates a new database connection at the specified path
-// Default path: ~/.config/ontop/ontop.db
+// ErrConflict is returned by UpdateTask when the task was modified by another
+// writer since it was read. Callers should reload the task and retry.
+var ErrConflict = errors.New("task was modified by someone else")
+
+// DBTX is implemented by both *sql.DB and *sql.Tx, so task queries can run
+// standalone or as part of a transaction
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// NewDB creates a new database connection at the specified path. An empty
+// path is resolved by config.ResolveDBPath: $ONTOP_DB, else the nearest
+// project database, else the global default,
+// $XDG_CONFIG_HOME/ontop/ontop.db or ~/.config/ontop/ontop.db.
 func NewDB(path string) (*sql.DB, error) {
-	// If path is empty, use default
 	if path == "" {
-		home, err := os.UserHomeDir()
+		resolved, _, err := config.ResolveDBPath("")
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return nil, fmt.Errorf("failed to find project database: %w", err)
+		}
+		path = resolved
+	}
+
+	// If path is still empty, use the global default
+	if path == "" {
+		configDir := os.Getenv("XDG_CONFIG_HOME")
+		if !filepath.IsAbs(configDir) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get home directory: %w", err)
+			}
+			configDir = filepath.Join(home, ".config")
 		}
-		ontopDir := filepath.Join(home, ".config", "ontop")
+		ontopDir := filepath.Join(configDir, "ontop")
 		if err := os.MkdirAll(ontopDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create .config/ontop directory: %w", err)
+			return nil, fmt.Errorf("failed to create %s directory: %w", ontopDir, err)
 		}
 		path = filepath.Join(ontopDir, "ontop.db")
 	}
 
-	db, err := sql.Open("sqlite", path)
+	// busy_timeout is per-connection, so it goes in the DSN to apply to every
+	// pooled connection. This lets the TUI, CLI and API server share one file.
+	// Transactions take the write lock up front so read-then-write
+	// transactions wait for other writers instead of failing with SQLITE_BUSY.
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -42,18 +76,32 @@ func NewDB(path string) (*sql.DB, error) {
 	return db, nil
 }
 
+// DBPath returns the file db was opened on
+func DBPath(db *sql.DB) (string, error) {
+	var path string
+	err := db.QueryRow("SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get database path: %w", err)
+	}
+	return path, nil
+}
+
 // CreateTask inserts a new task into the database
-func CreateTask(db *sql.DB, task *models.Task) error {
+func CreateTask(db DBTX, task *models.Task) error {
 	tagsJSON, err := json.Marshal(task.Tags)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tags: %w", err)
 	}
 
+	if task.Version == 0 {
+		task.Version = 1
+	}
+
 	query := `
 		INSERT INTO tasks (
 			id, title, description, priority, column, progress, parent_id,
-			archived, tags, created_at, updated_at, completed_at, deleted_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			archived, tags, created_at, updated_at, completed_at, deleted_at, version, due_at, board_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err = db.Exec(query,
@@ -70,6 +118,9 @@ func CreateTask(db *sql.DB, task *models.Task) error {
 		task.UpdatedAt.Format(time.RFC3339),
 		formatNullTime(task.CompletedAt),
 		formatNullTime(task.DeletedAt),
+		task.Version,
+		formatNullTime(task.DueAt),
+		task.BoardID,
 	)
 
 	if err != nil {
@@ -80,10 +131,10 @@ func CreateTask(db *sql.DB, task *models.Task) error {
 }
 
 // GetTask retrieves a single task by ID
-func GetTask(db *sql.DB, id string) (*models.Task, error) {
+func GetTask(db DBTX, id string) (*models.Task, error) {
 	query := `
 		SELECT id, title, description, priority, column, progress, parent_id,
-		       archived, tags, created_at, updated_at, completed_at, deleted_at
+		       archived, tags, created_at, updated_at, completed_at, deleted_at, version, due_at, board_id
 		FROM tasks
 		WHERE id = ? AND deleted_at IS NULL
 	`
@@ -92,21 +143,57 @@ func GetTask(db *sql.DB, id string) (*models.Task, error) {
 	return scanTask(row)
 }
 
+// FindTasksByIDPrefix returns non-deleted tasks (archived included) whose ID
+// starts with prefix, ordered by ID
+func FindTasksByIDPrefix(db DBTX, prefix string) ([]*models.Task, error) {
+	// Escape LIKE wildcards so the prefix is matched literally
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+
+	query := `
+		SELECT id, title, description, priority, column, progress, parent_id,
+		       archived, tags, created_at, updated_at, completed_at, deleted_at, version, due_at, board_id
+		FROM tasks
+		WHERE deleted_at IS NULL AND id LIKE ? ESCAPE '\'
+		ORDER BY id
+	`
+
+	rows, err := db.Query(query, escaped+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Best effort close
+	}()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
 // ListTasks retrieves tasks with optional filters
-func ListTasks(db *sql.DB, filters map[string]interface{}) ([]*models.Task, error) {
+func ListTasks(db DBTX, filters map[string]interface{}) ([]*models.Task, error) {
 	query := `
 		SELECT id, title, description, priority, column, progress, parent_id,
-		       archived, tags, created_at, updated_at, completed_at, deleted_at
+		       archived, tags, created_at, updated_at, completed_at, deleted_at, version, due_at, board_id
 		FROM tasks
 		WHERE deleted_at IS NULL
 	`
 	args := []interface{}{}
 
 	// Apply filters
-	if archived, ok := filters["archived"].(bool); ok {
+	// include_archived returns both active and archived tasks
+	includeArchived, _ := filters["include_archived"].(bool)
+	if archived, ok := filters["archived"].(bool); ok && !includeArchived {
 		query += " AND archived = ?"
 		args = append(args, archived)
-	} else {
+	} else if !includeArchived {
 		// Default: only show non-archived
 		query += " AND archived = false"
 	}
@@ -121,6 +208,11 @@ func ListTasks(db *sql.DB, filters map[string]interface{}) ([]*models.Task, erro
 		args = append(args, priority)
 	}
 
+	if boardID, ok := filters["board_id"].(string); ok {
+		query += " AND board_id = ?"
+		args = append(args, boardID)
+	}
+
 	if tag, ok := filters["tag"].(string); ok {
 		query += ` AND EXISTS (
 			SELECT 1 FROM json_each(tags)
@@ -151,8 +243,10 @@ func ListTasks(db *sql.DB, filters map[string]interface{}) ([]*models.Task, erro
 	return tasks, rows.Err()
 }
 
-// UpdateTask updates an existing task
-func UpdateTask(db *sql.DB, task *models.Task) error {
+// UpdateTask updates an existing task. The update only applies if the row
+// still has task.Version; otherwise another writer changed it first and
+// ErrConflict is returned. On success task.Version is incremented.
+func UpdateTask(db DBTX, task *models.Task) error {
 	tagsJSON, err := json.Marshal(task.Tags)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tags: %w", err)
@@ -162,11 +256,11 @@ func UpdateTask(db *sql.DB, task *models.Task) error {
 		UPDATE tasks
 		SET title = ?, description = ?, priority = ?, column = ?, progress = ?,
 		    parent_id = ?, archived = ?, tags = ?, updated_at = ?,
-		    completed_at = ?
-		WHERE id = ?
+		    completed_at = ?, due_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
 	`
 
-	_, err = db.Exec(query,
+	result, err := db.Exec(query,
 		task.Title,
 		task.Description,
 		task.Priority,
@@ -177,18 +271,48 @@ func UpdateTask(db *sql.DB, task *models.Task) error {
 		string(tagsJSON),
 		task.UpdatedAt.Format(time.RFC3339),
 		formatNullTime(task.CompletedAt),
+		formatNullTime(task.DueAt),
 		task.ID,
+		task.Version,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	if affected == 0 {
+		// Distinguish a missing task from one that changed underneath us
+		var exists int
+		err := db.QueryRow(`SELECT 1 FROM tasks WHERE id = ?`, task.ID).Scan(&exists)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to update task: task %s not found", task.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		return fmt.Errorf("%w: task %s", ErrConflict, task.ID)
+	}
+
+	task.Version++
 	return nil
 }
 
+// CountSubtasks returns the number of non-deleted subtasks of a task
+func CountSubtasks(db DBTX, parentID string) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM tasks WHERE parent_id = ? AND deleted_at IS NULL`, parentID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count subtasks: %w", err)
+	}
+	return count, nil
+}
+
 // DeleteTask soft-deletes a task and all its subtasks by setting deleted_at timestamp
-func DeleteTask(db *sql.DB, id string) error {
+func DeleteTask(db DBTX, id string) error {
 	now := time.Now().Format(time.RFC3339)
 
 	// First, soft-delete all subtasks
@@ -208,6 +332,56 @@ func DeleteTask(db *sql.DB, id string) error {
 	return nil
 }
 
+// ChangeDetector reports when another connection or process has committed
+// a write to the database, using SQLite's data_version pragma. It holds a
+// dedicated connection because data_version is tracked per connection.
+type ChangeDetector struct {
+	conn    *sql.Conn
+	version int64
+}
+
+// NewChangeDetector reserves a connection from db and records the current
+// data version as the baseline
+func NewChangeDetector(db *sql.DB) (*ChangeDetector, error) {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve connection: %w", err)
+	}
+
+	d := &ChangeDetector{conn: conn}
+	if d.version, err = d.dataVersion(); err != nil {
+		_ = conn.Close() // Ignore close error since we're already handling an error
+		return nil, err
+	}
+	return d, nil
+}
+
+// Changed reports whether the database was modified since the last call
+func (d *ChangeDetector) Changed() (bool, error) {
+	version, err := d.dataVersion()
+	if err != nil {
+		return false, err
+	}
+	if version == d.version {
+		return false, nil
+	}
+	d.version = version
+	return true, nil
+}
+
+// Close releases the detector's connection back to the pool
+func (d *ChangeDetector) Close() error {
+	return d.conn.Close()
+}
+
+func (d *ChangeDetector) dataVersion() (int64, error) {
+	var version int64
+	if err := d.conn.QueryRowContext(context.Background(), "PRAGMA data_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read data_version: %w", err)
+	}
+	return version, nil
+}
+
 // Helper functions
 
 type scanner interface {
@@ -218,7 +392,7 @@ func scanTask(s scanner) (*models.Task, error) {
 	var task models.Task
 	var tagsJSON string
 	var createdAtStr, updatedAtStr string
-	var completedAtStr, deletedAtStr sql.NullString
+	var completedAtStr, deletedAtStr, dueAtStr sql.NullString
 
 	err := s.Scan(
 		&task.ID,
@@ -234,6 +408,9 @@ func scanTask(s scanner) (*models.Task, error) {
 		&updatedAtStr,
 		&completedAtStr,
 		&deletedAtStr,
+		&task.Version,
+		&dueAtStr,
+		&task.BoardID,
 	)
 
 	if err != nil {
@@ -270,6 +447,14 @@ func scanTask(s scanner) (*models.Task, error) {
 		task.DeletedAt = &t
 	}
 
+	if dueAtStr.Valid {
+		t, err := time.Parse(time.RFC3339, dueAtStr.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse due_at: %w", err)
+		}
+		task.DueAt = &t
+	}
+
 	return &task, nil
 }
 
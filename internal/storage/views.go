@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// SaveView inserts a view, or replaces the filter, sort and layout of the
+// view with the same name (ignoring case)
+func SaveView(db DBTX, view *models.View) error {
+	_, err := db.Exec(`
+		INSERT INTO views (name, filter, sort, layout, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET filter = excluded.filter, sort = excluded.sort, layout = excluded.layout
+	`, view.Name, view.Filter, view.Sort, view.Layout, view.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+	return nil
+}
+
+// GetView retrieves a view by name, ignoring case. Returns an error wrapping
+// sql.ErrNoRows if there is none.
+func GetView(db DBTX, name string) (*models.View, error) {
+	return scanView(db.QueryRow(`SELECT name, filter, sort, layout, created_at FROM views WHERE name = ?`, name))
+}
+
+// ListViews returns all views by name
+func ListViews(db DBTX) ([]*models.View, error) {
+	rows, err := db.Query(`SELECT name, filter, sort, layout, created_at FROM views ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query views: %w", err)
+	}
+	defer func() {
+		_ = rows.Close() // Best effort close
+	}()
+
+	var views []*models.View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// DeleteView removes a view by name, ignoring case. Returns sql.ErrNoRows if
+// there is none.
+func DeleteView(db DBTX, name string) error {
+	result, err := db.Exec(`DELETE FROM views WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanView(s scanner) (*models.View, error) {
+	var view models.View
+	var createdAtStr string
+	if err := s.Scan(&view.Name, &view.Filter, &view.Sort, &view.Layout, &createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to scan view: %w", err)
+	}
+
+	var err error
+	if view.CreatedAt, err = time.Parse(time.RFC3339, createdAtStr); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	return &view, nil
+}
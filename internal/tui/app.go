@@ -1,13 +1,17 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/lucasefe/ontop/internal/config"
 	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
 	"github.com/lucasefe/ontop/internal/storage"
 )
 
@@ -16,15 +20,48 @@ type tasksLoadedMsg struct {
 	err   error
 }
 
+// dbChangedMsg reports the result of polling the database for external writes
+type dbChangedMsg struct {
+	changed bool
+	err     error
+}
+
+// changePollInterval is how often the TUI checks for writes from other processes
+const changePollInterval = time.Second
+
+// selectionSettledMsg is sent once the selection has stayed on a task for
+// selectionSettleDelay. seq tells whether it moved again since.
+type selectionSettledMsg struct {
+	seq int
+}
+
+// selectionSettleDelay is how long the selection has to stay on a task
+// before it is saved for @selected, so moving through a column saves once
+const selectionSettleDelay = 500 * time.Millisecond
+
 // Init initializes the model and loads tasks from database
 func (m Model) Init() tea.Cmd {
-	return m.loadTasks
+	return tea.Batch(m.loadTasks, m.watchChanges(), tea.EnableMouseCellMotion)
+}
+
+// watchChanges schedules the next check for external database writes.
+// Returns nil if change detection is unavailable.
+func (m Model) watchChanges() tea.Cmd {
+	if m.changes == nil {
+		return nil
+	}
+	changes := m.changes
+	return tea.Tick(changePollInterval, func(time.Time) tea.Msg {
+		changed, err := changes.Changed()
+		return dbChangedMsg{changed: changed, err: err}
+	})
 }
 
 // loadTasks loads tasks from the database based on showArchived flag
 func (m Model) loadTasks() tea.Msg {
 	filters := map[string]interface{}{
 		"archived": m.showArchived,
+		"board_id": m.boardID,
 	}
 	tasks, err := storage.ListTasks(m.db, filters)
 	if err != nil {
@@ -40,6 +77,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.help.Width = msg.Width
+		m.scrollToSelection()
 		return m, nil
 
 	case tasksLoadedMsg:
@@ -47,36 +85,148 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = msg.err
 			return m, tea.Quit
 		}
-		m.tasks = msg.tasks
 
-		// If we just moved a task, restore focus on it in its new location
-		if m.lastMovedTaskID != "" {
-			columnTasks := m.GetTasksByColumn(m.GetCurrentColumnName())
-			for i, task := range columnTasks {
-				if task.ID == m.lastMovedTaskID {
-					m.selectedTask = i
+		// Keep the selection on the same task across reloads. If we just moved
+		// a task, focus it in its new location instead.
+		focusID := m.lastMovedTaskID
+		if focusID == "" {
+			if task := m.GetSelectedTask(); task != nil {
+				focusID = task.ID
+			}
+		}
+		m.lastMovedTaskID = "" // Clear the tracking
+
+		m.allTasks = msg.tasks
+		m.tasks = m.filter.Apply(msg.tasks)
+		m.refreshFocus()
+		m.restoreSelection(focusID)
+		m.scrollToSelection()
+		m.pruneMarks()
+
+		// Refresh the task shown in detail view with its latest data
+		if m.detailTask != nil {
+			for _, task := range m.allTasks {
+				if task.ID == m.detailTask.ID {
+					m.detailTask = task
+					m.detailSubtasks = m.getSubtasksForTask(task.ID)
 					break
 				}
 			}
-			m.lastMovedTaskID = "" // Clear the tracking
 		}
 
 		return m, nil
 
+	case dbChangedMsg:
+		if msg.err != nil {
+			log.Printf("Warning: Failed to check for database changes: %v", msg.err)
+		}
+		if msg.changed {
+			return m, tea.Batch(m.loadTasks, m.watchChanges())
+		}
+		return m, m.watchChanges()
+
+	case selectionSettledMsg:
+		if msg.seq != m.selectionSeq {
+			return m, nil
+		}
+		return m.saveSelection()
+
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		updated, cmd := m.handleKeyPress(msg)
+		if next, ok := updated.(Model); ok {
+			next.scrollToSelection()
+			next, saveCmd := next.rememberSelection()
+			return next, tea.Batch(cmd, saveCmd)
+		}
+		return updated, cmd
+
+	case tea.MouseMsg:
+		updated, cmd := m.handleMouse(msg)
+		if next, ok := updated.(Model); ok {
+			next.scrollToSelection()
+			next, saveCmd := next.rememberSelection()
+			return next, tea.Batch(cmd, saveCmd)
+		}
+		return updated, cmd
 	}
 
 	return m, nil
 }
 
+// rememberSelection schedules saving the selected task for CLI '@selected'
+// references whenever the selection changes. It is saved once it settles,
+// rather than on every key press.
+func (m Model) rememberSelection() (Model, tea.Cmd) {
+	selectedID := m.shownTaskID()
+	if selectedID == m.selectionID {
+		return m, nil
+	}
+
+	m.selectionID = selectedID
+	m.selectionSeq++
+	seq := m.selectionSeq
+	return m, tea.Tick(selectionSettleDelay, func(time.Time) tea.Msg {
+		return selectionSettledMsg{seq: seq}
+	})
+}
+
+// saveSelection saves the selected task for '@selected' if it changed since
+// it was last saved
+func (m Model) saveSelection() (Model, tea.Cmd) {
+	selectedID := m.shownTaskID()
+	if selectedID == "" || selectedID == m.rememberedID {
+		return m, nil
+	}
+
+	m.rememberedID = selectedID
+	return m, m.saveState("selected task", func(s *config.State) { s.SelectedTaskID = selectedID })
+}
+
+// shownTaskID returns the ID of the task open in the detail view, or else of
+// the selected task; "" if there is none
+func (m Model) shownTaskID() string {
+	if m.viewMode == ViewModeDetail && m.detailTask != nil {
+		return m.detailTask.ID
+	}
+	if task := m.GetSelectedTask(); task != nil {
+		return task.ID
+	}
+	return ""
+}
+
+// quit quits the TUI, first saving a selection that hasn't settled yet
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	m, save := m.saveSelection()
+	if save != nil {
+		save() // Waits for the write, which would be lost on exit
+	}
+	return m, tea.Quit
+}
+
 // handleKeyPress processes keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	keys := m.keys
 
+	// The tag and quick-add prompts take typed text, so only ctrl+c quits there
+	if m.viewMode == ViewModeTagPrompt && msg.Type != tea.KeyCtrlC {
+		return m.handleTagPromptKeys(msg, keys)
+	}
+	if m.viewMode == ViewModeQuickAdd && msg.Type != tea.KeyCtrlC {
+		return m.handleQuickAddKeys(msg, keys)
+	}
+	if m.viewMode == ViewModeTemplate && msg.Type != tea.KeyCtrlC {
+		return m.handleTemplateKeys(msg, keys)
+	}
+	if m.viewMode == ViewModeFilter && msg.Type != tea.KeyCtrlC {
+		return m.handleFilterKeys(msg, keys)
+	}
+	if m.viewMode == ViewModePalette && msg.Type != tea.KeyCtrlC {
+		return m.handlePaletteKeys(msg, keys)
+	}
+
 	// Quit (should always work)
 	if key.Matches(msg, keys.Quit) {
-		return m, tea.Quit
+		return m.quit()
 	}
 
 	// Handle form modes first to allow "?" to be typed in text fields
@@ -100,6 +250,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleMoveKeys(msg, keys)
 	case ViewModeDeleteConfirm:
 		return m.handleDeleteConfirmKeys(msg, keys)
+	case ViewModeConflict:
+		return m.handleConflictKeys(msg, keys)
+	case ViewModeBoardPicker:
+		return m.handleBoardPickerKeys(msg, keys)
 	}
 
 	return m, nil
@@ -107,6 +261,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleKanbanKeys handles key presses in kanban view
 func (m Model) handleKanbanKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	// Multi-select: space, V, * and esc to clear
+	if next, handled := m.handleMarkKeys(msg, keys); handled {
+		return next, nil
+	}
+
 	// Context-sensitive navigation based on view layout
 	if m.viewLayout == LayoutRow {
 		// ROW MODE: up/down navigates tasks seamlessly, auto-switching rows at boundaries
@@ -182,109 +341,132 @@ func (m Model) handleKanbanKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd
 		}
 	}
 
+	// Page through the current column (or row)
+	if key.Matches(msg, keys.PageUp, keys.PageDown, keys.Home, keys.End) {
+		count := len(m.GetTasksByColumn(m.GetCurrentColumnName()))
+		page := m.windowSize(len(m.columnLines(m.currentColumn)))
+		switch {
+		case key.Matches(msg, keys.PageUp):
+			m.selectedTask -= page
+		case key.Matches(msg, keys.PageDown):
+			m.selectedTask += page
+		case key.Matches(msg, keys.Home):
+			m.selectedTask = 0
+		case key.Matches(msg, keys.End):
+			m.selectedTask = count - 1
+		}
+		m.selectedTask = max(min(m.selectedTask, count-1), 0)
+		return m, nil
+	}
+
+	// Collapse and expand subtasks
+	if key.Matches(msg, keys.Collapse) {
+		return m.toggleCollapse()
+	}
+	if key.Matches(msg, keys.CollapseAll) {
+		return m.collapseAll()
+	}
+	if key.Matches(msg, keys.ExpandAll) {
+		return m.expandAll()
+	}
+
+	// Zoom into the selected task, or back out to the whole board
+	if key.Matches(msg, keys.Focus) {
+		return m.toggleFocus()
+	}
+	if key.Matches(msg, keys.Back) && m.focus != nil {
+		return m.unfocus(), nil
+	}
+
 	// Quick move with Shift+navigation keys (context-sensitive)
 	if m.viewLayout == LayoutColumn {
 		// COLUMN MODE: Shift+H moves left (to previous workflow stage), Shift+L moves right (to next workflow stage)
 		if key.Matches(msg, keys.QuickMoveLeft) {
-			task := m.GetSelectedTask()
-			if task != nil && m.currentColumn > 0 {
-				m.moveTask = task
-				m.moveSelection = m.currentColumn - 1
-				return m.confirmMove()
-			}
-			return m, nil
+			return m.quickMove(m.currentColumn - 1)
 		}
 
 		if key.Matches(msg, keys.QuickMoveRight) {
-			task := m.GetSelectedTask()
-			if task != nil && m.currentColumn < 2 {
-				m.moveTask = task
-				m.moveSelection = m.currentColumn + 1
-				return m.confirmMove()
-			}
-			return m, nil
+			return m.quickMove(m.currentColumn + 1)
 		}
 	} else {
 		// ROW MODE: Shift+K moves up (to previous workflow stage), Shift+J moves down (to next workflow stage)
 		if key.Matches(msg, keys.QuickMoveUp) {
-			task := m.GetSelectedTask()
-			if task != nil && m.currentColumn > 0 {
-				m.moveTask = task
-				m.moveSelection = m.currentColumn - 1
-				return m.confirmMove()
-			}
-			return m, nil
+			return m.quickMove(m.currentColumn - 1)
 		}
 
 		if key.Matches(msg, keys.QuickMoveDown) {
-			task := m.GetSelectedTask()
-			if task != nil && m.currentColumn < 2 {
-				m.moveTask = task
-				m.moveSelection = m.currentColumn + 1
-				return m.confirmMove()
-			}
-			return m, nil
+			return m.quickMove(m.currentColumn + 1)
 		}
 	}
 
 	// Enter detail view
 	if key.Matches(msg, keys.Select) {
-		task := m.GetSelectedTask()
-		if task != nil {
-			m.viewMode = ViewModeDetail
-			m.detailTask = task
-			// Load subtasks
-			filters := map[string]interface{}{
-				"archived": false,
-			}
-			allTasks, err := storage.ListTasks(m.db, filters)
-			if err == nil {
-				m.detailSubtasks = []*models.Task{}
-				for _, t := range allTasks {
-					if t.ParentID != nil && *t.ParentID == task.ID {
-						m.detailSubtasks = append(m.detailSubtasks, t)
-					}
-				}
-			}
-		}
-		return m, nil
+		return m.openSelected()
 	}
 
 	// Refresh
 	if key.Matches(msg, keys.Refresh) {
-		return m, m.loadTasks
+		return m.refresh()
 	}
 
 	// Move task
 	if key.Matches(msg, keys.Move) {
-		task := m.GetSelectedTask()
-		if task != nil {
-			m.viewMode = ViewModeMove
-			m.moveTask = task
-			// Default to current column
-			m.moveSelection = m.currentColumn
-		}
-		return m, nil
+		return m.openMovePrompt()
 	}
 
 	// New task
 	if key.Matches(msg, keys.New) {
-		m.viewMode = ViewModeCreate
-		m.initCreateForm(nil) // Pass nil for top-level task
-		return m, nil
+		return m.openCreateForm()
+	}
+
+	// Quick add to the current column
+	if key.Matches(msg, keys.QuickAdd) {
+		return m.openQuickAdd()
 	}
 
 	// Toggle sort
 	if key.Matches(msg, keys.Sort) {
-		m.ToggleSortMode()
+		return m.cycleSort()
+	}
+
+	// Cycle swimlanes (none/priority/tag/parent)
+	if key.Matches(msg, keys.Swimlanes) {
+		return m.cycleSwimlanes()
+	}
+
+	// Jump between swimlanes
+	if key.Matches(msg, keys.NextLane) {
+		m.jumpLane(1)
+		return m, nil
+	}
+	if key.Matches(msg, keys.PreviousLane) {
+		m.jumpLane(-1)
 		return m, nil
 	}
 
 	// Toggle archived view
 	if key.Matches(msg, keys.ToggleArchive) {
-		m.showArchived = !m.showArchived
-		m.selectedTask = 0
-		return m, m.loadTasks
+		return m.toggleArchived()
+	}
+
+	// Filter the board
+	if key.Matches(msg, keys.Filter) {
+		return m.openFilterBar()
+	}
+
+	// Cycle through the saved views
+	if key.Matches(msg, keys.NextView) {
+		return m.nextView()
+	}
+
+	// Search and run any action
+	if key.Matches(msg, keys.Palette) {
+		return m.openPalette()
+	}
+
+	// Switch board
+	if key.Matches(msg, keys.Board) {
+		return m.openBoardPicker()
 	}
 
 	// Toggle view layout (column vs row)
@@ -294,34 +476,175 @@ func (m Model) handleKanbanKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd
 
 	// Archive/Unarchive task (toggle based on current view)
 	if key.Matches(msg, keys.Archive) {
-		task := m.GetSelectedTask()
-		if task != nil {
-			// Toggle: if viewing archived, unarchive; if viewing active, archive
-			task.Archived = !m.showArchived
-			task.UpdatedAt = time.Now()
-			if err := storage.UpdateTask(m.db, task); err != nil {
-				m.err = err
-				return m, tea.Quit
-			}
-			return m, m.loadTasks
-		}
-		return m, nil
+		return m.archiveTasks()
 	}
 
 	// Delete task (show confirmation)
 	if key.Matches(msg, keys.Delete) {
-		task := m.GetSelectedTask()
-		if task != nil {
-			m.viewMode = ViewModeDeleteConfirm
-			m.deleteTask = task
-			m.moveSelection = 0 // Default to "No"
-		}
+		return m.confirmDelete()
+	}
+
+	// Edit tags of the marked or selected tasks
+	if key.Matches(msg, keys.Tag) {
+		return m.openTagPrompt()
+	}
+
+	// Set priority of the marked or selected tasks
+	if key.Matches(msg, keys.Priority) {
+		return m.setPriority(slices.Index(keys.Priority.Keys(), msg.String()) + 1) // The keys are P1 to P5
+	}
+
+	return m, nil
+}
+
+// The board actions below are run by their keys in kanban view, and by the
+// command palette.
+
+// toggleFocus zooms into the selected task, or back out to the whole board
+func (m Model) toggleFocus() (tea.Model, tea.Cmd) {
+	if m.focus != nil {
+		return m.unfocus(), nil
+	}
+	return m.focusTask(), nil
+}
+
+// openSelected shows the selected task in detail view
+func (m Model) openSelected() (tea.Model, tea.Cmd) {
+	if task := m.GetSelectedTask(); task != nil {
+		m = m.openDetail(task)
+	}
+	return m, nil
+}
+
+// refresh reloads the tasks
+func (m Model) refresh() (tea.Model, tea.Cmd) {
+	return m, m.loadTasks
+}
+
+// openMovePrompt asks where to move the marked or selected tasks
+func (m Model) openMovePrompt() (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		m.viewMode = ViewModeMove
+		m.bulkIDs = m.markedIDs()
+		m.moveTask = nil
+		m.moveSelection = m.currentColumn
 		return m, nil
 	}
+	task := m.GetSelectedTask()
+	if task != nil {
+		m.viewMode = ViewModeMove
+		m.moveTask = task
+		// Default to current column
+		m.moveSelection = m.currentColumn
+	}
+	return m, nil
+}
 
+// openCreateForm opens the form for a new task
+func (m Model) openCreateForm() (tea.Model, tea.Cmd) {
+	m.viewMode = ViewModeCreate
+	m.initCreateForm(m.focusParentID()) // Subtask of the focused task, if any
+	return m, nil
+}
+
+// cycleSort switches to the next sort mode and saves it as the preference
+func (m Model) cycleSort() (tea.Model, tea.Cmd) {
+	m.ToggleSortMode()
+	sort := config.SortModes[m.sortMode]
+	return m, saveConfig("sort preference", func(cfg *config.Config) { cfg.UI.Sort = sort })
+}
+
+// cycleSwimlanes switches to the next swimlane mode and saves it as the
+// preference
+func (m Model) cycleSwimlanes() (tea.Model, tea.Cmd) {
+	m.ToggleSwimlanes()
+	swimlanes := config.SwimlaneModes[m.swimlanes]
+	return m, saveConfig("swimlanes preference", func(cfg *config.Config) { cfg.UI.Swimlanes = swimlanes })
+}
+
+// toggleArchived switches between the active and the archived tasks and
+// saves it as the preference
+func (m Model) toggleArchived() (tea.Model, tea.Cmd) {
+	m.showArchived = !m.showArchived
+	m.selectedTask = 0
+	m.clearMarks()
+	showArchived := m.showArchived
+	return m, tea.Batch(m.loadTasks, saveConfig("archive view preference", func(cfg *config.Config) { cfg.UI.ShowArchived = showArchived }))
+}
+
+// archiveTasks archives the marked or selected tasks, or unarchives them when
+// viewing archived tasks
+func (m Model) archiveTasks() (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		archived := !m.showArchived
+		status := "Archived %d task(s)"
+		if !archived {
+			status = "Unarchived %d task(s)"
+		}
+		return m.applyBulk(m.markedIDs(), service.TaskPatch{Archived: &archived}, status)
+	}
+	task := m.GetSelectedTask()
+	if task != nil {
+		// Toggle: if viewing archived, unarchive; if viewing active, archive
+		archived := !m.showArchived
+		_, err := m.svc.Update(task.ID, service.TaskPatch{Archived: &archived, Version: &task.Version})
+		if err != nil {
+			if errors.Is(err, storage.ErrConflict) {
+				m.statusMessage = "Task was changed elsewhere; reloaded, try again"
+				return m, m.loadTasks
+			}
+			m.err = err
+			return m, tea.Quit
+		}
+		return m, m.loadTasks
+	}
+	return m, nil
+}
+
+// confirmDelete asks to confirm deleting the marked or selected tasks
+func (m Model) confirmDelete() (tea.Model, tea.Cmd) {
+	if len(m.marked) > 0 {
+		m.viewMode = ViewModeDeleteConfirm
+		m.bulkIDs = m.markedIDs()
+		m.deleteTask = nil
+		m.moveSelection = 0 // Default to "No"
+		return m, nil
+	}
+	task := m.GetSelectedTask()
+	if task != nil {
+		m.viewMode = ViewModeDeleteConfirm
+		m.deleteTask = task
+		m.moveSelection = 0 // Default to "No"
+	}
 	return m, nil
 }
 
+// setPriority sets the priority of the marked or selected tasks
+func (m Model) setPriority(priority int) (tea.Model, tea.Cmd) {
+	status := fmt.Sprintf("Set priority P%d on ", priority) + "%d task(s)"
+	return m.applyBulk(m.targetIDs(), service.TaskPatch{Priority: &priority}, status)
+}
+
+// openDetail shows task in detail view
+func (m Model) openDetail(task *models.Task) Model {
+	m.viewMode = ViewModeDetail
+	m.detailTask = task
+	// Load subtasks
+	filters := map[string]interface{}{
+		"archived": false,
+	}
+	allTasks, err := storage.ListTasks(m.db, filters)
+	if err == nil {
+		m.detailSubtasks = []*models.Task{}
+		for _, t := range allTasks {
+			if t.ParentID != nil && *t.ParentID == task.ID {
+				m.detailSubtasks = append(m.detailSubtasks, t)
+			}
+		}
+	}
+	return m
+}
+
 // handleDetailKeys handles key presses in detail view
 func (m Model) handleDetailKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
 	// Back to kanban
@@ -338,7 +661,7 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd
 		if m.detailTask != nil {
 			m.viewMode = ViewModeCreate
 			m.initCreateForm(&m.detailTask.ID) // Pass parent ID to pre-fill
-			m.statusMessage = ""                // Clear status message
+			m.statusMessage = ""               // Clear status message
 		}
 		return m, nil
 	}
@@ -357,9 +680,13 @@ func (m Model) handleDetailKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd
 	if key.Matches(msg, keys.Archive) {
 		if m.detailTask != nil {
 			// Toggle: if viewing archived, unarchive; if viewing active, archive
-			m.detailTask.Archived = !m.showArchived
-			m.detailTask.UpdatedAt = time.Now()
-			if err := storage.UpdateTask(m.db, m.detailTask); err != nil {
+			archived := !m.showArchived
+			_, err := m.svc.Update(m.detailTask.ID, service.TaskPatch{Archived: &archived, Version: &m.detailTask.Version})
+			if err != nil {
+				if errors.Is(err, storage.ErrConflict) {
+					m.statusMessage = "Task was changed elsewhere; reloaded, try again"
+					return m, m.loadTasks
+				}
 				m.err = err
 				return m, tea.Quit
 			}
@@ -390,6 +717,7 @@ func (m Model) handleMoveKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd)
 	if key.Matches(msg, keys.Back) {
 		m.viewMode = ViewModeKanban
 		m.moveTask = nil
+		m.bulkIDs = nil
 		return m, nil
 	}
 
@@ -422,6 +750,7 @@ func (m Model) handleDeleteConfirmKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model,
 	if key.Matches(msg, keys.Back) {
 		m.viewMode = ViewModeKanban
 		m.deleteTask = nil
+		m.bulkIDs = nil
 		return m, nil
 	}
 
@@ -442,9 +771,17 @@ func (m Model) handleDeleteConfirmKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model,
 
 	// Confirm
 	if key.Matches(msg, keys.Select) {
+		if len(m.bulkIDs) > 0 {
+			if m.moveSelection == 1 { // Yes, delete
+				return m.confirmBulkDelete()
+			}
+			m.viewMode = ViewModeKanban
+			m.bulkIDs = nil
+			return m, nil
+		}
 		if m.moveSelection == 1 { // Yes, delete
 			if m.deleteTask != nil {
-				if err := storage.DeleteTask(m.db, m.deleteTask.ID); err != nil {
+				if err := m.svc.Delete(m.deleteTask.ID); err != nil && !errors.Is(err, service.ErrTaskNotFound) {
 					m.err = err
 					return m, tea.Quit
 				}
@@ -479,6 +816,11 @@ func (m Model) handleFormKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd)
 		return m.saveForm()
 	}
 
+	// Create from a template instead (new tasks only)
+	if key.Matches(msg, keys.Template) && m.viewMode == ViewModeCreate {
+		return m.openTemplatePicker()
+	}
+
 	// Shift+Tab to previous field (total 6 fields: 0-5)
 	// 0: Title, 1: Description (textarea), 2: Priority, 3: Progress, 4: Tags, 5: Parent ID
 	if key.Matches(msg, keys.ShiftTab) {
@@ -576,6 +918,9 @@ func (m Model) handleFormKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd)
 
 // confirmMove performs the actual task move operation
 func (m Model) confirmMove() (tea.Model, tea.Cmd) {
+	if len(m.bulkIDs) > 0 {
+		return m.confirmBulkMove()
+	}
 	if m.moveTask == nil {
 		m.viewMode = ViewModeKanban
 		return m, nil
@@ -592,24 +937,15 @@ func (m Model) confirmMove() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Update task
-	oldColumn := m.moveTask.Column
-	m.moveTask.Column = targetColumn
-	m.moveTask.UpdatedAt = time.Now()
-
-	// Set completed_at if moving to done
-	if targetColumn == models.ColumnDone && m.moveTask.CompletedAt == nil {
-		now := time.Now()
-		m.moveTask.CompletedAt = &now
-	}
-
-	// Clear completed_at if moving from done
-	if oldColumn == models.ColumnDone && targetColumn != models.ColumnDone {
-		m.moveTask.CompletedAt = nil
-	}
-
-	// Save to database
-	if err := storage.UpdateTask(m.db, m.moveTask); err != nil {
+	// Save to database (completed_at is kept in sync by the service)
+	_, err := m.svc.Update(m.moveTask.ID, service.TaskPatch{Column: &targetColumn, Version: &m.moveTask.Version})
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			m.statusMessage = "Task was changed elsewhere; reloaded, try again"
+			m.viewMode = ViewModeKanban
+			m.moveTask = nil
+			return m, m.loadTasks
+		}
 		m.err = err
 		return m, tea.Quit
 	}
@@ -626,6 +962,27 @@ func (m Model) confirmMove() (tea.Model, tea.Cmd) {
 	return m, m.loadTasks
 }
 
+// quickMove moves the marked tasks, or the selected task, to the column at
+// index target without opening the move prompt
+func (m Model) quickMove(target int) (tea.Model, tea.Cmd) {
+	if target < 0 || target > 2 {
+		return m, nil
+	}
+	m.moveSelection = target
+
+	if len(m.marked) > 0 {
+		m.bulkIDs = m.markedIDs()
+		return m.confirmMove()
+	}
+
+	task := m.GetSelectedTask()
+	if task == nil {
+		return m, nil
+	}
+	m.moveTask = task
+	return m.confirmMove()
+}
+
 // View renders the UI
 func (m Model) View() string {
 	if m.err != nil {
@@ -633,7 +990,7 @@ func (m Model) View() string {
 	}
 
 	switch m.viewMode {
-	case ViewModeKanban:
+	case ViewModeKanban, ViewModeFilter:
 		if m.viewLayout == LayoutRow {
 			return m.renderKanbanRows()
 		}
@@ -646,6 +1003,18 @@ func (m Model) View() string {
 		return m.renderDeleteConfirm()
 	case ViewModeCreate, ViewModeEdit:
 		return m.renderForm()
+	case ViewModeConflict:
+		return m.renderConflict()
+	case ViewModeTagPrompt:
+		return m.renderTagPrompt()
+	case ViewModeQuickAdd:
+		return m.renderQuickAdd()
+	case ViewModeTemplate:
+		return m.renderTemplatePicker()
+	case ViewModeBoardPicker:
+		return m.renderBoardPicker()
+	case ViewModePalette:
+		return m.renderPalette()
 	}
 
 	return ""
@@ -653,31 +1022,38 @@ func (m Model) View() string {
 
 // handleToggleView toggles between column and row layouts
 func (m Model) handleToggleView() (Model, tea.Cmd) {
-	// Toggle layout
 	if m.viewLayout == LayoutColumn {
-		m.viewLayout = LayoutRow
+		m.setLayout(LayoutRow)
 	} else {
-		m.viewLayout = LayoutColumn
+		m.setLayout(LayoutColumn)
 	}
 
-	// Reset scroll offsets when toggling
-	m.rowScrollOffset = make(map[int]int)
+	// Save preference to config in the background
+	viewMode := layoutName(m.viewLayout)
+	return m, saveConfig("view mode preference", func(cfg *config.Config) { cfg.UI.ViewMode = viewMode })
+}
 
-	// Save preference to config asynchronously
-	go func() {
-		cfg := config.Config{
-			UI: config.UIConfig{
-				ViewMode: "column",
-			},
-		}
-		if m.viewLayout == LayoutRow {
-			cfg.UI.ViewMode = "row"
-		}
+// saveConfig queues a change to the config file and returns a command that
+// logs a failed save. Changes are queued here, on the update loop, so they
+// are written in the order the user made them.
+func saveConfig(what string, fn func(*config.Config)) tea.Cmd {
+	return logFailedSave(what, config.UpdateAsync(fn))
+}
 
-		if err := config.Save(cfg); err != nil {
-			log.Printf("Failed to save view mode preference: %v", err)
-		}
-	}()
+// saveState queues a change to the session state of the database like
+// saveConfig, so changes like the selected task are saved in the order they
+// happened
+func (m Model) saveState(what string, fn func(*config.State)) tea.Cmd {
+	return logFailedSave(what, config.UpdateStateAsync(m.dbFile, fn))
+}
 
-	return m, nil
+// logFailedSave returns a command that waits for a queued save and logs it
+// if it failed
+func logFailedSave(what string, done <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		if err := <-done; err != nil {
+			log.Printf("Failed to save %s: %v", what, err)
+		}
+		return nil
+	}
 }
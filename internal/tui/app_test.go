@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// newTestModel creates a model on a fresh database holding tasks, with the
+// tasks loaded. Config and session state go to a temp directory.
+func newTestModel(t *testing.T, tasks ...*models.Task) Model {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+
+	db, err := storage.NewDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := storage.InitSchema(db); err != nil {
+		t.Fatalf("Failed to init schema: %v", err)
+	}
+
+	svc := service.NewTaskService(db)
+	for _, task := range tasks {
+		if err := svc.Create(task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	m := NewModel(db)
+	updated, _ := m.Update(m.loadTasks())
+	return updated.(Model)
+}
+
+// press sends a key to m, returning the updated model and its command
+func press(t *testing.T, m Model, k string) (Model, tea.Cmd) {
+	t.Helper()
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	switch k {
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	case " ":
+		msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	updated, cmd := m.Update(msg)
+	next, ok := updated.(Model)
+	if !ok {
+		t.Fatalf("Unexpected model %T", updated)
+	}
+	return next, cmd
+}
+
+// selectedInState returns the task saved for @selected
+func selectedInState(t *testing.T, m Model) string {
+	t.Helper()
+	state, err := config.LoadState(m.dbFile)
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	return state.SelectedTaskID
+}
+
+// TestRememberSelection tests the selected task is saved for @selected once
+// the selection settles, not on every move
+func TestRememberSelection(t *testing.T) {
+	tasks := []*models.Task{
+		{Title: "First", Priority: 1, Column: models.ColumnInbox},
+		{Title: "Second", Priority: 2, Column: models.ColumnInbox},
+		{Title: "Third", Priority: 3, Column: models.ColumnInbox},
+	}
+	m := newTestModel(t, tasks...)
+
+	m, _ = press(t, m, "j")
+	first := m.selectionSeq
+	m, _ = press(t, m, "j")
+	if got := selectedInState(t, m); got != "" {
+		t.Fatalf("Expected nothing saved while moving, got %s", got)
+	}
+
+	updated, cmd := m.Update(selectionSettledMsg{seq: first})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Errorf("Expected a selection that moved on not to be saved")
+	}
+
+	updated, cmd = m.Update(selectionSettledMsg{seq: m.selectionSeq})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected the settled selection to be saved")
+	}
+	cmd() // Waits for the write
+	if got := selectedInState(t, m); got != tasks[2].ID {
+		t.Errorf("Expected %s saved, got %s", tasks[2].ID, got)
+	}
+
+	// Quitting saves a selection that hasn't settled
+	m, _ = press(t, m, "k")
+	press(t, m, "q")
+	if got := selectedInState(t, m); got != tasks[1].ID {
+		t.Errorf("Expected %s saved on quit, got %s", tasks[1].ID, got)
+	}
+}
@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// openBoardPicker lists the boards to switch to, with the current one
+// selected
+func (m Model) openBoardPicker() (Model, tea.Cmd) {
+	boards, err := service.NewBoardService(m.db).List()
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to list boards: %v", err)
+		return m, nil
+	}
+	counts, err := storage.CountTasksByBoard(m.db)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Failed to count tasks: %v", err)
+		return m, nil
+	}
+
+	m.boards = boards
+	m.boardCounts = counts
+	m.boardSelection = 0
+	for i, board := range boards {
+		if board.ID == m.boardID {
+			m.boardSelection = i
+		}
+	}
+	m.viewMode = ViewModeBoardPicker
+	return m, nil
+}
+
+// handleBoardPickerKeys handles key presses in the board picker
+func (m Model) handleBoardPickerKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Board):
+		m.viewMode = ViewModeKanban
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.boardSelection > 0 {
+			m.boardSelection--
+		}
+
+	case key.Matches(msg, keys.Down):
+		if m.boardSelection < len(m.boards)-1 {
+			m.boardSelection++
+		}
+
+	case key.Matches(msg, keys.Select):
+		m.viewMode = ViewModeKanban
+		return m.switchBoard(m.boards[m.boardSelection])
+	}
+	return m, nil
+}
+
+// switchBoard shows board's tasks and remembers it as the last used board
+func (m Model) switchBoard(board *models.Board) (Model, tea.Cmd) {
+	if board.ID == m.boardID {
+		return m, nil
+	}
+
+	m.boardID = board.ID
+	m.boardName = board.Name
+	m.currentColumn = 0
+	m.selectedTask = 0
+	m.clearMarks()
+	m.statusMessage = "Switched to board " + board.Name
+
+	name := board.Name
+	if board.ID == models.DefaultBoardID {
+		name = ""
+	}
+	return m, tea.Batch(m.loadTasks, saveConfig("current board", func(cfg *config.Config) { cfg.Board = name }))
+}
+
+// renderBoardPicker renders the board picker
+func (m Model) renderBoardPicker() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGreen).
+		Render("Switch Board")
+	b.WriteString(title + "\n\n")
+
+	var prompt strings.Builder
+	prompt.WriteString("Select a board:\n\n")
+	for i, board := range m.boards {
+		line := fmt.Sprintf("%-20s %d tasks", board.Name, m.boardCounts[board.ID])
+		if board.ID == m.boardID {
+			line += " (current)"
+		}
+		if i == m.boardSelection {
+			prompt.WriteString(selectedTaskStyle.Render("> "+line) + "\n")
+		} else {
+			prompt.WriteString(taskStyle.Render("  "+line) + "\n")
+		}
+	}
+	prompt.WriteString("\n")
+	prompt.WriteString(formHelpStyle.Render("enter: switch • esc: cancel • create boards with 'ontop board create'"))
+
+	b.WriteString(boardPickerStyle.Render(prompt.String()))
+	return b.String()
+}
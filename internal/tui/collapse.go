@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// collapseMarker starts the summary shown on collapsed parent cards
+const collapseMarker = "▸"
+
+// toggleCollapse hides or shows the subtasks of the selected parent. On a
+// subtask, it collapses the subtask's parent and selects it.
+func (m Model) toggleCollapse() (Model, tea.Cmd) {
+	task := m.GetSelectedTask()
+	if task == nil {
+		return m, nil
+	}
+	parent := task
+	if p := m.displayParent(task); p != nil {
+		parent = p
+	}
+	if total, _ := m.subtaskCounts(parent.ID); total == 0 {
+		m.statusMessage = "No subtasks to collapse"
+		return m, nil
+	}
+
+	if m.collapsed[parent.ID] {
+		delete(m.collapsed, parent.ID)
+	} else {
+		m.collapsed[parent.ID] = true
+	}
+	m.restoreSelection(parent.ID)
+	return m, m.saveCollapsed()
+}
+
+// collapseAll hides the subtasks of every parent on the board
+func (m Model) collapseAll() (Model, tea.Cmd) {
+	selected := m.GetSelectedTask()
+	for _, task := range m.tasks {
+		if task.ParentID != nil {
+			m.collapsed[*task.ParentID] = true
+		}
+	}
+
+	// Keep the selection on the task, or on its parent if it is now hidden
+	if selected != nil {
+		if parent := m.displayParent(selected); parent != nil {
+			selected = parent
+		}
+		m.restoreSelection(selected.ID)
+	}
+	return m, m.saveCollapsed()
+}
+
+// expandAll shows every subtask again
+func (m Model) expandAll() (Model, tea.Cmd) {
+	selected := m.GetSelectedTask()
+	clear(m.collapsed)
+	if selected != nil {
+		m.restoreSelection(selected.ID)
+	}
+	return m, m.saveCollapsed()
+}
+
+// displayParent returns the parent a subtask is shown under, or nil if it
+// is shown on its own because its parent is in another column or focused
+func (m *Model) displayParent(task *models.Task) *models.Task {
+	if task.ParentID == nil || m.focus != nil {
+		return nil
+	}
+	parent := m.findTask(*task.ParentID)
+	if parent == nil || parent.Column != task.Column {
+		return nil
+	}
+	return parent
+}
+
+// saveCollapsed returns a command remembering the collapsed parents for the
+// next session. Parents that were deleted or archived since they were
+// collapsed are dropped; ones on other boards are kept.
+func (m Model) saveCollapsed() tea.Cmd {
+	var ids, unloaded []string
+	for id := range m.collapsed {
+		if m.loadedTask(id) != nil {
+			ids = append(ids, id)
+		} else {
+			unloaded = append(unloaded, id)
+		}
+	}
+	db := m.db
+	return m.saveState("collapsed tasks", func(s *config.State) {
+		// Looked up here, on the writer, to keep the database off the update loop
+		for _, id := range unloaded {
+			task, err := storage.GetTask(db, id)
+			if errors.Is(err, sql.ErrNoRows) || err == nil && task.Archived {
+				continue
+			}
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		s.Collapsed = ids
+	})
+}
+
+// subtaskCounts returns how many subtasks a task has on the board, in any
+// column, and how many of them are done
+func (m Model) subtaskCounts(parentID string) (total, done int) {
+	for _, task := range m.tasks {
+		if task.ParentID != nil && *task.ParentID == parentID {
+			total++
+			if task.Column == models.ColumnDone {
+				done++
+			}
+		}
+	}
+	return total, done
+}
+
+// collapseSummary returns the summary shown on a collapsed parent's card,
+// like "▸ 3 subtasks (1 done)", or "" if the task isn't collapsed. Shorter
+// forms are used when the full one is wider than width.
+func (m Model) collapseSummary(task *models.Task, width int) string {
+	if !m.collapsed[task.ID] {
+		return ""
+	}
+	total, done := m.subtaskCounts(task.ID)
+	if total == 0 {
+		return ""
+	}
+	noun := "subtasks"
+	if total == 1 {
+		noun = "subtask"
+	}
+	summaries := []string{
+		fmt.Sprintf("%s %d %s (%d done)", collapseMarker, total, noun, done),
+		fmt.Sprintf("%s %d (%d done)", collapseMarker, total, done),
+	}
+	for _, summary := range summaries {
+		if lipgloss.Width(summary) <= width {
+			return summary
+		}
+	}
+	return fmt.Sprintf("%s %d", collapseMarker, total)
+}
@@ -7,50 +7,39 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	confirmPromptStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(gruvboxRed).
-				Padding(1, 2).
-				MarginTop(2).
-				MarginBottom(2)
-
-	confirmButtonStyle = lipgloss.NewStyle().
-				Padding(0, 2).
-				MarginRight(2)
-
-	confirmButtonSelectedStyle = lipgloss.NewStyle().
-					Background(gruvboxRed).
-					Foreground(gruvboxBg0).
-					Bold(true).
-					Padding(0, 2).
-					MarginRight(2)
-)
-
 // renderDeleteConfirm renders the delete confirmation dialog
 func (m Model) renderDeleteConfirm() string {
-	if m.deleteTask == nil {
+	bulk := len(m.bulkIDs) > 0
+	if m.deleteTask == nil && !bulk {
 		return "No task selected"
 	}
 
 	var b strings.Builder
 
 	// Title
+	titleText := "⚠  Delete Task?"
+	if bulk {
+		titleText = fmt.Sprintf("⚠  Delete %d Tasks?", len(m.bulkIDs))
+	}
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(gruvboxRed).
-		Render("⚠  Delete Task?")
+		Foreground(colorRed).
+		Render(titleText)
 	b.WriteString(title + "\n\n")
 
 	// Task info
-	taskInfo := lipgloss.NewStyle().
-		Foreground(gruvboxGray).
-		Render(fmt.Sprintf("Task: %s", m.deleteTask.Description))
-	b.WriteString(taskInfo + "\n\n")
+	if bulk {
+		b.WriteString(m.renderBulkTargets() + "\n")
+	} else {
+		taskInfo := lipgloss.NewStyle().
+			Foreground(colorGray).
+			Render(fmt.Sprintf("Task: %s", m.deleteTask.Description))
+		b.WriteString(taskInfo + "\n\n")
+	}
 
 	// Warning
 	warning := lipgloss.NewStyle().
-		Foreground(gruvboxYellow).
+		Foreground(colorYellow).
 		Render("This action cannot be undone!")
 	b.WriteString(warning + "\n\n")
 
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// Conflict resolution options, in display order
+const (
+	conflictReload = iota
+	conflictOverwrite
+	conflictMerge
+)
+
+var conflictOptions = []string{"Reload theirs", "Overwrite with mine", "Merge"}
+
+// showConflict switches to the conflict prompt after an edit failed to save
+// because the task changed in the database since the form was opened
+func (m Model) showConflict(edited *models.Task) (tea.Model, tea.Cmd) {
+	latest, err := storage.GetTask(m.db, edited.ID)
+	if err != nil {
+		m.formErr = fmt.Errorf("task was changed elsewhere and could not be reloaded: %v", err)
+		return m, nil
+	}
+
+	m.conflictTask = latest
+	m.conflictEdit = edited
+	m.conflictSelection = conflictMerge
+	m.viewMode = ViewModeConflict
+	return m, nil
+}
+
+// handleConflictKeys handles key presses in the edit conflict prompt
+func (m Model) handleConflictKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	// Back to the form with local edits intact
+	if key.Matches(msg, keys.Back) {
+		m.viewMode = ViewModeEdit
+		m.conflictTask = nil
+		m.conflictEdit = nil
+		return m, nil
+	}
+
+	if key.Matches(msg, keys.Left) {
+		if m.conflictSelection > 0 {
+			m.conflictSelection--
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, keys.Right) {
+		if m.conflictSelection < len(conflictOptions)-1 {
+			m.conflictSelection++
+		}
+		return m, nil
+	}
+
+	if key.Matches(msg, keys.Select) {
+		return m.resolveConflict()
+	}
+
+	return m, nil
+}
+
+// resolveConflict applies the selected conflict resolution
+func (m Model) resolveConflict() (tea.Model, tea.Cmd) {
+	latest := m.conflictTask
+	edited := m.conflictEdit
+	base := m.formTask
+	m.conflictTask = nil
+	m.conflictEdit = nil
+
+	switch m.conflictSelection {
+	case conflictReload:
+		// Discard local edits and edit the latest version
+		m.viewMode = ViewModeEdit
+		m.initEditForm(latest)
+		m.formErr = errors.New("reloaded the latest version; your edits were discarded")
+		return m, nil
+
+	case conflictMerge:
+		// Prefill the form with both sides combined so the user can review it
+		merged := service.MergeTaskEdits(base, edited, latest)
+		m.viewMode = ViewModeEdit
+		m.initEditForm(merged)
+		m.formTask = latest
+		m.formErr = errors.New("merged with the latest version; review and press Ctrl+S to save")
+		return m, nil
+	}
+
+	// Overwrite: save local edits on top of the latest version
+	edited.Version = latest.Version
+	saved, err := m.svc.Update(edited.ID, formPatch(edited))
+	if err != nil {
+		if errors.Is(err, storage.ErrConflict) {
+			return m.showConflict(edited)
+		}
+		m.viewMode = ViewModeEdit
+		m.formTask = latest
+		return m.handleSaveError(err)
+	}
+
+	m.statusMessage = "Task updated successfully (overwrote other changes)"
+	m.detailTask = saved
+	m.viewMode = ViewModeDetail
+	m.formInputs = nil
+	m.formTask = nil
+	m.formErr = nil
+	return m, m.loadTasks
+}
+
+// renderConflict renders the edit conflict resolution prompt
+func (m Model) renderConflict() string {
+	if m.conflictTask == nil || m.conflictEdit == nil {
+		return "No conflict"
+	}
+
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorYellow).
+		Render("⚠  Task Changed Elsewhere")
+	b.WriteString(title + "\n\n")
+
+	info := lipgloss.NewStyle().
+		Foreground(colorGray).
+		Render(fmt.Sprintf("Task: %s\nAnother process updated this task while you were editing it.", m.conflictTask.Title))
+	b.WriteString(info + "\n\n")
+
+	// Show fields that differ between the stored version and the local edit
+	var diff strings.Builder
+	diff.WriteString(formLabelStyle.Render("Theirs → Mine") + "\n\n")
+	for _, line := range conflictDiffLines(m.conflictTask, m.conflictEdit) {
+		diff.WriteString("  " + line + "\n")
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(diff.String())
+	prompt.WriteString("\nHow do you want to resolve it?\n\n")
+	for i, option := range conflictOptions {
+		if i == m.conflictSelection {
+			prompt.WriteString(moveSelectedStyle.Render(option))
+		} else {
+			prompt.WriteString(moveOptionStyle.Render(option))
+		}
+	}
+
+	b.WriteString(movePromptStyle.Render(prompt.String()))
+	b.WriteString("\n")
+
+	helpView := m.help.View(m.keys)
+	b.WriteString(helpStyle.Render(helpView))
+
+	return b.String()
+}
+
+// conflictDiffLines describes each field that differs between two versions
+func conflictDiffLines(theirs, mine *models.Task) []string {
+	var lines []string
+	add := func(field, a, b string) {
+		if a != b {
+			lines = append(lines, fmt.Sprintf("%-12s %s → %s", field+":", a, b))
+		}
+	}
+
+	add("Title", theirs.Title, mine.Title)
+	if theirs.Description != mine.Description {
+		lines = append(lines, "Description: (changed)")
+	}
+	add("Priority", fmt.Sprintf("P%d", theirs.Priority), fmt.Sprintf("P%d", mine.Priority))
+	add("Column", formatColumnName(theirs.Column), formatColumnName(mine.Column))
+	add("Progress", fmt.Sprintf("%d%%", theirs.Progress), fmt.Sprintf("%d%%", mine.Progress))
+	add("Tags", strings.Join(theirs.Tags, ","), strings.Join(mine.Tags, ","))
+	add("Parent", derefOr(theirs.ParentID, "(none)"), derefOr(mine.ParentID, "(none)"))
+
+	if len(lines) == 0 {
+		lines = append(lines, "(no field differences)")
+	}
+	return lines
+}
+
+func derefOr(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
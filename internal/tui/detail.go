@@ -7,21 +7,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	detailLabelStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(gruvboxGray)
-
-	detailValueStyle = lipgloss.NewStyle().
-				Foreground(gruvboxFg)
-
-	detailSectionStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(gruvboxGray).
-				Padding(1, 2).
-				MarginBottom(1)
-)
-
 // renderDetail renders the detailed task view
 func (m Model) renderDetail() string {
 	if m.detailTask == nil {
@@ -40,7 +25,7 @@ func (m Model) renderDetail() string {
 	// Title header
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(gruvboxGreen).
+		Foreground(colorGreen).
 		Width(contentWidth).
 		Align(lipgloss.Center)
 	b.WriteString(titleStyle.Render("Task Details") + "\n\n")
@@ -48,7 +33,7 @@ func (m Model) renderDetail() string {
 	// Status message (if present)
 	if m.statusMessage != "" {
 		statusStyle := lipgloss.NewStyle().
-			Foreground(gruvboxGreen).
+			Foreground(colorGreen).
 			Bold(true).
 			Width(contentWidth).
 			Align(lipgloss.Center)
@@ -84,7 +69,7 @@ func (m Model) renderDetail() string {
 	// Priority
 	priorityColor, ok := priorityColors[task.Priority]
 	if !ok {
-		priorityColor = gruvboxGray
+		priorityColor = colorGray
 	}
 	priorityStyle := lipgloss.NewStyle().
 		Foreground(priorityColor).
@@ -111,7 +96,7 @@ func (m Model) renderDetail() string {
 	details.WriteString(detailLabelStyle.Render("Tags: "))
 	if len(task.Tags) > 0 {
 		tagStyle := lipgloss.NewStyle().
-			Foreground(gruvboxAqua).
+			Foreground(colorAqua).
 			Bold(true)
 		for i, tag := range task.Tags {
 			if i > 0 {
@@ -131,7 +116,15 @@ func (m Model) renderDetail() string {
 		archivedStr = "Yes"
 	}
 	details.WriteString(detailValueStyle.Render(archivedStr))
-	details.WriteString("\n\n")
+	details.WriteString("\n")
+
+	// Due date
+	if task.DueAt != nil {
+		details.WriteString(detailLabelStyle.Render("Due: "))
+		details.WriteString(detailValueStyle.Render(task.DueAt.Format("Mon 2006-01-02")))
+		details.WriteString("\n")
+	}
+	details.WriteString("\n")
 
 	// Timestamps
 	details.WriteString(detailLabelStyle.Render("Created: "))
@@ -158,7 +151,7 @@ func (m Model) renderDetail() string {
 		var subtasks strings.Builder
 		subtasksTitle := lipgloss.NewStyle().
 			Bold(true).
-			Foreground(gruvboxYellow).
+			Foreground(colorYellow).
 			Render(fmt.Sprintf("Subtasks (%d)", len(m.detailSubtasks)))
 		subtasks.WriteString(subtasksTitle)
 		subtasks.WriteString("\n\n")
@@ -167,7 +160,7 @@ func (m Model) renderDetail() string {
 			// Priority and title/description
 			priorityColor, ok := priorityColors[st.Priority]
 			if !ok {
-				priorityColor = gruvboxGray
+				priorityColor = colorGray
 			}
 			priorityStyle := lipgloss.NewStyle().
 				Foreground(priorityColor).
@@ -190,7 +183,7 @@ func (m Model) renderDetail() string {
 			}
 
 			// Column indicator
-			columnStyle := lipgloss.NewStyle().Foreground(gruvboxGray)
+			columnStyle := lipgloss.NewStyle().Foreground(colorGray)
 			subtasks.WriteString(" " + columnStyle.Render("["+formatColumnShort(st.Column)+"]"))
 
 			subtasks.WriteString("\n")
@@ -212,8 +205,8 @@ func renderProgressBar(progress int, width int) string {
 	filled := int(float64(progress) / 100.0 * float64(width))
 	empty := width - filled
 
-	filledStyle := lipgloss.NewStyle().Foreground(gruvboxGreen)
-	emptyStyle := lipgloss.NewStyle().Foreground(gruvboxGray)
+	filledStyle := lipgloss.NewStyle().Foreground(colorGreen)
+	emptyStyle := lipgloss.NewStyle().Foreground(colorGray)
 
 	bar := filledStyle.Render(strings.Repeat("█", filled)) +
 		emptyStyle.Render(strings.Repeat("░", empty))
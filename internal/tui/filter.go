@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// openFilterBar opens the filter bar under the board, starting from the
+// current filter
+func (m Model) openFilterBar() (Model, tea.Cmd) {
+	m.filterInput = textinput.New()
+	m.filterInput.Prompt = "Filter: "
+	m.filterInput.Placeholder = `tag:bug priority:<=2 -tag:wontfix "login"`
+	m.filterInput.CharLimit = 500
+	m.filterInput.Width = 60
+	m.filterInput.SetValue(m.filterExpr)
+	m.filterInput.Focus()
+	m.filterBefore = m.filterExpr
+	m.savingView = false
+	m.formErr = nil
+	m.viewMode = ViewModeFilter
+	return m, textinput.Blink
+}
+
+// handleFilterKeys handles key presses in the filter bar. The board is
+// filtered as the expression is typed; esc restores the previous filter.
+func (m Model) handleFilterKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	if m.savingView {
+		return m.handleSaveViewKeys(msg, keys)
+	}
+
+	switch {
+	case key.Matches(msg, keys.Back):
+		_ = m.setFilter(m.filterBefore) // Was valid when applied
+		m.viewMode = ViewModeKanban
+		m.formErr = nil
+		return m, nil
+
+	case key.Matches(msg, keys.Select):
+		if m.formErr != nil {
+			return m, nil // Keep the bar open to fix the expression
+		}
+		if m.view != nil && m.filterExpr != m.view.Filter {
+			m.view = nil // Edited into a different filter
+		}
+		m.viewMode = ViewModeKanban
+		return m, nil
+
+	case key.Matches(msg, keys.Save):
+		if m.formErr != nil {
+			return m, nil
+		}
+		if m.filterExpr == "" {
+			m.formErr = errors.New("type a filter to save it as a view")
+			return m, nil
+		}
+		m.savingView = true
+		m.filterInput.Prompt = "Save view as: "
+		m.filterInput.Placeholder = "triage"
+		m.filterInput.SetValue("")
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	m.formErr = m.setFilter(m.filterInput.Value())
+	return m, cmd
+}
+
+// handleSaveViewKeys handles key presses while naming the view the current
+// filter, sort and layout are saved as
+func (m Model) handleSaveViewKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		// Back to editing the filter
+		m.savingView = false
+		m.filterInput.Prompt = "Filter: "
+		m.filterInput.SetValue(m.filterExpr)
+		m.formErr = nil
+		return m, nil
+
+	case key.Matches(msg, keys.Select):
+		var err error
+		if m, err = m.saveView(m.filterInput.Value()); err != nil {
+			var validationErr *service.ValidationError
+			if errors.As(err, &validationErr) {
+				m.formErr = err
+				return m, nil
+			}
+			m.err = err
+			return m, tea.Quit
+		}
+		m.viewMode = ViewModeKanban
+		m.savingView = false
+		m.formErr = nil
+		m.statusMessage = fmt.Sprintf("Saved view %s", m.view.Name)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return m, cmd
+}
+
+// setFilter filters the board by expr. An invalid expression leaves the
+// current filter in place and is returned.
+func (m *Model) setFilter(expr string) error {
+	filter, err := service.ParseFilter(expr, time.Now())
+	if err != nil {
+		return err
+	}
+	m.filter = filter
+	m.filterExpr = strings.TrimSpace(expr)
+	m.applyFilter()
+	return nil
+}
+
+// applyFilter narrows the loaded tasks down to the ones matching the filter,
+// keeping the selection on the same task if it still matches
+func (m *Model) applyFilter() {
+	selectedID := m.selectedID()
+	m.tasks = m.filter.Apply(m.allTasks)
+	m.restoreSelection(selectedID)
+	m.pruneMarks()
+}
+
+// filterStatus returns the status bar segment for the filter, with the
+// number of matching tasks, or "" if there is none
+func (m Model) filterStatus() string {
+	if m.filterExpr == "" {
+		return ""
+	}
+	return fmt.Sprintf("  •  Filter: %s (%d of %d)", m.filterExpr, len(m.tasks), len(m.allTasks))
+}
+
+// renderFilterBar renders the filter bar shown under the board in place of
+// the help, on one line: the input followed by an error or the keys
+func (m Model) renderFilterBar() string {
+	hint := formHelpStyle.Render("enter: apply • ctrl+s: save as view • esc: cancel")
+	if m.savingView {
+		hint = formHelpStyle.Render("enter: save view • esc: back to filter")
+	}
+	if m.formErr != nil {
+		hint = lipgloss.NewStyle().
+			Foreground(colorRed).
+			Bold(true).
+			Render(m.formErr.Error())
+	}
+	return m.filterInput.View() + "  " + hint
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// breadcrumbSeparator separates the levels of the breadcrumb shown while
+// focused on a task
+const breadcrumbSeparator = " › "
+
+// focusTask zooms into the selected task, showing only its subtasks across
+// the columns. On a subtask, it focuses the subtask's parent and keeps the
+// subtask selected.
+func (m Model) focusTask() Model {
+	selected := m.GetSelectedTask()
+	if selected == nil {
+		return m
+	}
+	parent := selected
+	if selected.ParentID != nil {
+		parent = m.loadedTask(*selected.ParentID)
+		if parent == nil {
+			m.statusMessage = "Parent task is not on this board"
+			return m
+		}
+	}
+
+	m.focus = parent
+	m.clearMarks()
+	m.selectedTask = 0
+	if parent == selected {
+		m.restoreSelection("")
+	} else {
+		m.restoreSelection(selected.ID)
+	}
+	m.statusMessage = ""
+	return m
+}
+
+// unfocus pops out of the focused task back to the whole board, selecting
+// the task that was focused
+func (m Model) unfocus() Model {
+	focusID := m.focus.ID
+	m.focus = nil
+	m.clearMarks()
+	m.restoreSelection(focusID)
+	m.statusMessage = ""
+	return m
+}
+
+// inFocus reports whether task is shown: every task when not focused,
+// otherwise only the subtasks of the focused task
+func (m *Model) inFocus(task *models.Task) bool {
+	if m.focus == nil {
+		return true
+	}
+	return task.ParentID != nil && *task.ParentID == m.focus.ID
+}
+
+// refreshFocus updates the focused task after a reload, leaving focus if the
+// task is no longer on the board (deleted, archived or on another board).
+// The focused task doesn't need to match the filter.
+func (m *Model) refreshFocus() {
+	if m.focus == nil {
+		return
+	}
+	if task := m.loadedTask(m.focus.ID); task != nil {
+		m.focus = task
+		return
+	}
+	m.statusMessage = fmt.Sprintf("Left %s: it is no longer on the board", m.focus.Title)
+	m.focus = nil
+}
+
+// loadedTask returns the loaded task with the given ID, whether it matches
+// the filter or not, or nil if there is none
+func (m *Model) loadedTask(id string) *models.Task {
+	for _, task := range m.allTasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// focusParentID returns the ID of the focused task, the default parent of
+// new tasks, or nil when not focused
+func (m *Model) focusParentID() *string {
+	if m.focus == nil {
+		return nil
+	}
+	id := m.focus.ID
+	return &id
+}
+
+// renderTitle renders the title line of the board, followed by the
+// breadcrumb ("All › Implement auth") while focused on a task
+func (m Model) renderTitle() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGreen).
+		Render("OnTop - Task Manager")
+	if m.focus == nil {
+		return title
+	}
+
+	root := "All" + breadcrumbSeparator
+	focusTitle := m.focus.Title
+	maxLen := m.width - lipgloss.Width(title) - 2 - lipgloss.Width(root)
+	if runes := []rune(focusTitle); len(runes) > maxLen && maxLen > 3 {
+		focusTitle = string(runes[:maxLen-3]) + "..."
+	}
+	crumbs := lipgloss.NewStyle().Foreground(colorGray).Render(root) +
+		lipgloss.NewStyle().Bold(true).Foreground(colorFg).Render(focusTitle)
+	return title + "  " + crumbs
+}
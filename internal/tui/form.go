@@ -1,36 +1,21 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
 	"github.com/lucasefe/ontop/internal/models"
 	"github.com/lucasefe/ontop/internal/service"
 	"github.com/lucasefe/ontop/internal/storage"
 )
 
-var (
-	formStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(gruvboxGreen).
-			Padding(1, 2).
-			MarginTop(1)
-
-	formLabelStyle = lipgloss.NewStyle().
-			Foreground(gruvboxGray).
-			Bold(true)
-
-	formHelpStyle = lipgloss.NewStyle().
-			Foreground(gruvboxGray).
-			Italic(true)
-)
-
 // initCreateForm initializes the form for creating a new task
 // parentID can be provided to pre-fill the parent task ID field (for creating subtasks)
 func (m *Model) initCreateForm(parentID *string) {
@@ -70,7 +55,7 @@ func (m *Model) initCreateForm(parentID *string) {
 
 	// Parent task ID
 	m.formInputs[4] = textinput.New()
-	m.formInputs[4].Placeholder = "Parent task ID (optional)"
+	m.formInputs[4].Placeholder = "Parent task: ID, ID prefix, #N or @last (optional)"
 	m.formInputs[4].Width = inputWidth
 
 	// Pre-fill parent ID if provided
@@ -130,7 +115,7 @@ func (m *Model) initEditForm(task *models.Task) {
 
 	// Parent task ID
 	m.formInputs[4] = textinput.New()
-	m.formInputs[4].Placeholder = "Parent task ID (clear to remove parent)"
+	m.formInputs[4].Placeholder = "Parent task: ID, ID prefix, #N or @last (clear to remove parent)"
 	if task.ParentID != nil {
 		m.formInputs[4].SetValue(*task.ParentID)
 	}
@@ -166,7 +151,7 @@ func (m Model) renderForm() string {
 	}
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(gruvboxGreen).
+		Foreground(colorGreen).
 		Width(contentWidth).
 		Align(lipgloss.Center)
 	b.WriteString(titleStyle.Render(title) + "\n\n")
@@ -174,6 +159,11 @@ func (m Model) renderForm() string {
 	// Form content
 	var formContent strings.Builder
 
+	// Template hint (new tasks only)
+	if m.viewMode == ViewModeCreate {
+		formContent.WriteString(formHelpStyle.Render("Ctrl+T: create from a template (with predefined subtasks)") + "\n\n")
+	}
+
 	// Title field
 	formContent.WriteString(formLabelStyle.Render("Title:") + "\n")
 	formContent.WriteString(m.formInputs[0].View() + "\n\n")
@@ -233,7 +223,7 @@ func (m Model) renderForm() string {
 	// Show form error if present
 	if m.formErr != nil {
 		errorStyle := lipgloss.NewStyle().
-			Foreground(gruvboxRed).
+			Foreground(colorRed).
 			Bold(true).
 			MarginTop(1)
 		b.WriteString("\n")
@@ -246,7 +236,7 @@ func (m Model) renderForm() string {
 // getSubtasksForTask returns all subtasks for a given task ID
 func (m *Model) getSubtasksForTask(parentID string) []*models.Task {
 	var subtasks []*models.Task
-	for _, task := range m.tasks {
+	for _, task := range m.allTasks {
 		if task.ParentID != nil && *task.ParentID == parentID {
 			subtasks = append(subtasks, task)
 		}
@@ -306,49 +296,34 @@ func (m Model) saveForm() (tea.Model, tea.Cmd) {
 		tags = []string{}
 	}
 
-	// Parse parent ID
+	// Resolve the parent reference (existence and nesting are validated by the service)
 	var parentID *string
 	if parentIDStr != "" {
-		// Validate parent exists
-		parent, err := storage.GetTask(m.db, parentIDStr)
+		resolved, err := m.resolveTaskRef(parentIDStr)
 		if err != nil {
-			m.formErr = fmt.Errorf("parent task not found: %s", parentIDStr)
-			return m, nil
-		}
-
-		// Prevent multi-level nesting (subtasks cannot have subtasks)
-		if parent.ParentID != nil {
-			m.formErr = fmt.Errorf("cannot create subtask of subtask (max 1 level nesting)")
+			m.formErr = err
 			return m, nil
 		}
-
-		parentID = &parentIDStr
+		parentID = &resolved
 	}
 
-	now := time.Now()
 	var savedTaskID string
 
 	if m.viewMode == ViewModeCreate {
 		// Create new task
 		task := &models.Task{
-			ID:          service.GenerateID(),
 			Title:       title,
 			Description: description,
 			Priority:    priority,
 			Column:      m.GetCurrentColumnName(), // Use current column
 			Progress:    progress,
 			ParentID:    parentID,
-			Archived:    false,
+			BoardID:     m.boardID,
 			Tags:        tags,
-			CreatedAt:   now,
-			UpdatedAt:   now,
-			CompletedAt: nil,
-			DeletedAt:   nil,
 		}
 
-		if err := storage.CreateTask(m.db, task); err != nil {
-			m.err = err
-			return m, tea.Quit
+		if err := m.svc.Create(task); err != nil {
+			return m.handleSaveError(err)
 		}
 		savedTaskID = task.ID
 		m.statusMessage = "Task created successfully"
@@ -359,22 +334,29 @@ func (m Model) saveForm() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		m.formTask.Title = title
-		m.formTask.Description = description
-		m.formTask.Priority = priority
-		m.formTask.Progress = progress
-		m.formTask.Tags = tags
-		m.formTask.ParentID = parentID
-		m.formTask.UpdatedAt = now
-
-		if err := storage.UpdateTask(m.db, m.formTask); err != nil {
-			m.err = err
-			return m, tea.Quit
+		// Edit a copy so formTask keeps the originally loaded values,
+		// which a merge needs if the task changed underneath us
+		edited := *m.formTask
+		edited.Title = title
+		edited.Description = description
+		edited.Priority = priority
+		edited.Progress = progress
+		edited.Tags = tags
+		edited.ParentID = parentID
+
+		if _, err := m.svc.Update(edited.ID, formPatch(&edited)); err != nil {
+			if errors.Is(err, storage.ErrConflict) {
+				return m.showConflict(&edited)
+			}
+			return m.handleSaveError(err)
 		}
-		savedTaskID = m.formTask.ID
+		savedTaskID = edited.ID
 		m.statusMessage = "Task updated successfully"
 	}
 
+	// Make the saved task the target of @last
+	saveLast := m.saveState("last task", func(s *config.State) { s.LastTaskID = savedTaskID })
+
 	// Load the saved task for detail view
 	savedTask, err := storage.GetTask(m.db, savedTaskID)
 	if err != nil {
@@ -383,7 +365,7 @@ func (m Model) saveForm() (tea.Model, tea.Cmd) {
 		m.formInputs = nil
 		m.formTask = nil
 		m.formErr = nil
-		return m, m.loadTasks
+		return m, tea.Batch(m.loadTasks, saveLast)
 	}
 
 	// Go to detail view of the saved task
@@ -392,5 +374,50 @@ func (m Model) saveForm() (tea.Model, tea.Cmd) {
 	m.formInputs = nil
 	m.formTask = nil
 	m.formErr = nil
-	return m, m.loadTasks
+	return m, tea.Batch(m.loadTasks, saveLast)
+}
+
+// formPatch builds a patch of every form field from an edited task,
+// expecting the task's version to still be current
+func formPatch(edited *models.Task) service.TaskPatch {
+	parentID := ""
+	if edited.ParentID != nil {
+		parentID = *edited.ParentID
+	}
+	tags := edited.Tags
+	version := edited.Version
+	return service.TaskPatch{
+		Title:       &edited.Title,
+		Description: &edited.Description,
+		Priority:    &edited.Priority,
+		Progress:    &edited.Progress,
+		Tags:        &tags,
+		ParentID:    &parentID,
+		Version:     &version,
+	}
+}
+
+// resolveTaskRef resolves a task reference typed in the form, with
+// @selected referring to the task selected on the board
+func (m *Model) resolveTaskRef(ref string) (string, error) {
+	resolver := service.NewResolver(m.db)
+	if state, err := config.LoadState(m.dbFile); err == nil {
+		resolver.Aliases = state.Aliases
+		resolver.Last = state.LastTaskID
+	}
+	if task := m.GetSelectedTask(); task != nil {
+		resolver.Selected = task.ID
+	}
+	return resolver.Resolve(ref)
+}
+
+// handleSaveError shows validation errors on the form and quits on anything else
+func (m Model) handleSaveError(err error) (tea.Model, tea.Cmd) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		m.formErr = validationErr
+		return m, nil
+	}
+	m.err = err
+	return m, tea.Quit
 }
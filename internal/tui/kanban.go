@@ -8,80 +8,16 @@ import (
 	"github.com/lucasefe/ontop/internal/models"
 )
 
-var (
-	// Gruvbox colors
-	gruvboxBg0    = lipgloss.Color("#282828")
-	gruvboxBg2    = lipgloss.Color("#504945")
-	gruvboxFg     = lipgloss.Color("#ebdbb2")
-	gruvboxGray   = lipgloss.Color("#928374")
-	gruvboxRed    = lipgloss.Color("#fb4934")
-	gruvboxOrange = lipgloss.Color("#fe8019")
-	gruvboxYellow = lipgloss.Color("#fabd2f")
-	gruvboxGreen  = lipgloss.Color("#b8bb26")
-	gruvboxAqua   = lipgloss.Color("#8ec07c")
-
-	// Column styles (width will be set dynamically)
-	columnStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(gruvboxGray).
-			Padding(0, 1)
-
-	activeColumnStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(gruvboxGreen).
-				Padding(0, 1)
-
-	// Column header styles
-	inboxHeaderStyle = lipgloss.NewStyle().
-				Foreground(gruvboxAqua).
-				Bold(true)
-
-	inProgressHeaderStyle = lipgloss.NewStyle().
-				Foreground(gruvboxYellow).
-				Bold(true)
-
-	doneHeaderStyle = lipgloss.NewStyle().
-			Foreground(gruvboxGreen).
-			Bold(true)
-
-	// Task card styles
-	taskStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			MarginBottom(0)
-
-	selectedTaskStyle = lipgloss.NewStyle().
-				Background(gruvboxBg2).
-				Foreground(gruvboxFg).
-				Padding(0, 1).
-				MarginBottom(0)
-
-	priorityColors = map[int]lipgloss.Color{
-		1: gruvboxRed,
-		2: gruvboxOrange,
-		3: gruvboxYellow,
-		4: gruvboxGreen,
-		5: gruvboxGray,
-	}
-
-	// Status bar style
-	statusBarStyle = lipgloss.NewStyle().
-			Foreground(gruvboxGray).
-			MarginTop(1)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(gruvboxGray)
-)
+// columnTitles are the headers of the board's columns (or rows), in the
+// order of models.ValidColumns
+var columnTitles = []string{"INBOX", "IN PROGRESS", "DONE"}
 
 // renderKanban renders the kanban board view
 func (m Model) renderKanban() string {
 	var b strings.Builder
 
 	// Title
-	title := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(gruvboxGreen).
-		Render("OnTop - Task Manager")
-	b.WriteString(title + "\n\n")
+	b.WriteString(m.renderTitle() + "\n\n")
 
 	// Get tasks by column
 	inboxTasks := m.GetTasksByColumn(models.ColumnInbox)
@@ -89,9 +25,9 @@ func (m Model) renderKanban() string {
 	doneTasks := m.GetTasksByColumn(models.ColumnDone)
 
 	// Render columns side by side
-	inboxCol := m.renderColumn("INBOX", inboxTasks, 0)
-	inProgressCol := m.renderColumn("IN PROGRESS", inProgressTasks, 1)
-	doneCol := m.renderColumn("DONE", doneTasks, 2)
+	inboxCol := m.renderColumn(columnTitles[0], inboxTasks, 0)
+	inProgressCol := m.renderColumn(columnTitles[1], inProgressTasks, 1)
+	doneCol := m.renderColumn(columnTitles[2], doneTasks, 2)
 
 	columns := lipgloss.JoinHorizontal(
 		lipgloss.Top,
@@ -108,13 +44,25 @@ func (m Model) renderKanban() string {
 	if m.showArchived {
 		viewMode = "Archived"
 	}
-	statusMsg := fmt.Sprintf("Total tasks: %d  •  Sort: %s  •  View: %s", len(m.tasks), m.GetSortModeName(), viewMode)
+	statusMsg := fmt.Sprintf("DB: %s  •  Board: %s  •  Total tasks: %d  •  Sort: %s", m.dbPath, m.boardName, len(m.allTasks), m.GetSortModeName())
+	statusMsg += m.viewStatus()
+	statusMsg += fmt.Sprintf("  •  View: %s", viewMode)
+	statusMsg += m.filterStatus()
+	statusMsg += m.swimlaneStatus()
+	statusMsg += m.markedStatus()
+	if m.statusMessage != "" {
+		statusMsg += "  •  " + m.statusMessage
+	}
 	b.WriteString(statusBarStyle.Render(statusMsg))
 	b.WriteString("\n")
 
-	// Help view
-	helpView := m.help.View(m.keys)
-	b.WriteString(helpStyle.Render(helpView))
+	// Help view, or the filter bar while typing a filter
+	if m.viewMode == ViewModeFilter {
+		b.WriteString(m.renderFilterBar())
+	} else {
+		helpView := m.help.View(m.keys)
+		b.WriteString(helpStyle.Render(helpView))
+	}
 
 	return b.String()
 }
@@ -122,16 +70,7 @@ func (m Model) renderKanban() string {
 // renderColumn renders a single column with its tasks
 func (m Model) renderColumn(title string, tasks []*models.Task, columnIndex int) string {
 	var b strings.Builder
-
-	// Calculate column width based on terminal width
-	// Leave space for borders and padding: 3 columns + margins
-	columnWidth := (m.width - 10) / 3
-	if columnWidth < 25 {
-		columnWidth = 25 // Minimum width
-	}
-	if columnWidth > 50 {
-		columnWidth = 50 // Maximum width
-	}
+	columnWidth := m.columnWidth()
 
 	// Column header
 	var headerStyle lipgloss.Style
@@ -148,43 +87,151 @@ func (m Model) renderColumn(title string, tasks []*models.Task, columnIndex int)
 	b.WriteString(headerStyle.Render(header))
 	b.WriteString("\n\n")
 
-	// Calculate max tasks based on terminal height
-	maxTasks := (m.height - 10) / 3 // Roughly 3 lines per task
-	if maxTasks < 5 {
-		maxTasks = 5
+	b.WriteString(m.renderTaskList(tasks, columnIndex, columnWidth-4)) // Subtract padding
+
+	// Apply column style with dynamic width
+	content := b.String()
+	if columnIndex == m.currentColumn {
+		return activeColumnStyle.Width(columnWidth).Render(content)
 	}
+	return columnStyle.Width(columnWidth).Render(content)
+}
 
-	// Render tasks
-	for i, task := range tasks {
-		if i >= maxTasks {
-			remaining := len(tasks) - maxTasks
-			b.WriteString(taskStyle.Render(fmt.Sprintf("... +%d more", remaining)))
-			break
-		}
+// columnWidth returns the width of a column in column layout, without its
+// borders
+func (m Model) columnWidth() int {
+	// Leave space for borders and padding: 3 columns + margins
+	columnWidth := (m.width - 10) / 3
+	if columnWidth < 25 {
+		columnWidth = 25 // Minimum width
+	}
+	if columnWidth > 50 {
+		columnWidth = 50 // Maximum width
+	}
+	return columnWidth
+}
 
-		isSelected := columnIndex == m.currentColumn && i == m.selectedTask
-		isSubtask := task.ParentID != nil
-		cardContent := m.renderTaskCard(task, columnWidth-4, isSubtask) // Subtract padding
+// renderTaskList renders the lines of a column's (or row's) task list that
+// are scrolled into view: one card per task and the swimlane headers, with
+// "↑ N more"/"↓ N more" lines for the tasks above and below. Only the
+// visible cards are rendered.
+func (m Model) renderTaskList(tasks []*models.Task, column, cardWidth int) string {
+	lines := m.columnLines(column)
+	if len(lines) == 0 {
+		return taskStyle.Render("(no tasks)")
+	}
 
-		if isSelected {
-			b.WriteString(selectedTaskStyle.Render(cardContent))
-		} else {
-			b.WriteString(taskStyle.Render(cardContent))
+	// more renders a scroll indicator, or a blank line if only padding is
+	// out of view, to keep swimlanes aligned
+	more := func(arrow string, lines []boardLine) string {
+		count := 0
+		for _, line := range lines {
+			if line.task >= 0 {
+				count++
+			}
 		}
-		b.WriteString("\n")
+		if count == 0 {
+			return ""
+		}
+		return taskStyle.Render(fmt.Sprintf("%s %d more", arrow, count))
 	}
 
-	// Empty column message
-	if len(tasks) == 0 {
-		b.WriteString(taskStyle.Render("(no tasks)"))
+	var rendered []string
+	start, end := m.taskWindow(column, len(lines))
+	if start > 0 {
+		rendered = append(rendered, more("↑", lines[:start]))
+	}
+	for _, line := range lines[start:end] {
+		switch {
+		case line.lane != nil:
+			count := len(line.lane.tasks[models.ValidColumns()[column]])
+			rendered = append(rendered, renderLaneHeader(line.lane, count, cardWidth))
+		case line.task < 0:
+			rendered = append(rendered, "") // Padding
+		default:
+			task := tasks[line.task]
+			isSelected := column == m.currentColumn && line.task == m.selectedTask
+			isSubtask := task.ParentID != nil && m.focus == nil // Focused subtasks are shown on their own
+			cardContent := m.renderTaskCard(task, cardWidth, isSubtask)
+			rendered = append(rendered, m.cardStyle(task, isSelected).Render(cardContent))
+		}
+	}
+	if end < len(lines) {
+		rendered = append(rendered, more("↓", lines[end:]))
 	}
+	return strings.Join(rendered, "\n")
+}
 
-	// Apply column style with dynamic width
-	content := b.String()
-	if columnIndex == m.currentColumn {
-		return activeColumnStyle.Width(columnWidth).Render(content)
+// taskLines returns how many lines a column (or row) has for task cards and
+// scroll indicators, so that the board fits the terminal
+func (m Model) taskLines() int {
+	// Title and blank line, the blank line and status bar (with its margin)
+	// under the board, and the help
+	chrome := boardTop + 3 + lipgloss.Height(m.help.View(m.keys))
+	// Each column or row adds its borders, header and the blank line under it
+	if m.viewLayout == LayoutRow {
+		return max((m.height-chrome)/3-4, 1)
 	}
-	return columnStyle.Width(columnWidth).Render(content)
+	return max(m.height-chrome-4, 1)
+}
+
+// scrollOffsets returns the first visible task of each column, by column
+// index, for the current layout
+func (m Model) scrollOffsets() map[int]int {
+	if m.viewLayout == LayoutRow {
+		return m.rowScrollOffset
+	}
+	return m.columnScrollOffset
+}
+
+// windowSize returns how many of a column's count lines fit in view. Two
+// lines are kept for the scroll indicators when they don't all fit.
+func (m Model) windowSize(count int) int {
+	lines := m.taskLines()
+	if count <= lines {
+		return count
+	}
+	return max(lines-2, 1)
+}
+
+// taskWindow returns the range of a column's count lines that is scrolled
+// into view
+func (m Model) taskWindow(column, count int) (start, end int) {
+	size := m.windowSize(count)
+	start = max(min(m.scrollOffsets()[column], count-size), 0)
+	return start, start + size
+}
+
+// scrollToSelection scrolls the current column just enough to show the
+// selected task, along with its swimlane header if it is the lane's first
+func (m *Model) scrollToSelection() {
+	lines := m.columnLines(m.currentColumn)
+	selected := max(taskLine(lines, m.selectedTask), 0)
+	first := selected
+	if selected > 0 && lines[selected-1].lane != nil {
+		first--
+	}
+	start, end := m.taskWindow(m.currentColumn, len(lines))
+	switch {
+	case first < start:
+		start = first
+	case selected >= end:
+		start = selected - m.windowSize(len(lines)) + 1
+	}
+	m.setScrollOffset(m.currentColumn, max(start, 0))
+}
+
+// setScrollOffset sets the first visible line of a column. Swimlanes in
+// column layout scroll all columns together, to keep the lanes aligned.
+func (m *Model) setScrollOffset(column, offset int) {
+	offsets := m.scrollOffsets()
+	if m.swimlanes != SwimlaneNone && m.viewLayout == LayoutColumn {
+		for i := range models.ValidColumns() {
+			offsets[i] = offset
+		}
+		return
+	}
+	offsets[column] = offset
 }
 
 // renderTaskCard renders a single task card as a single line
@@ -194,11 +241,14 @@ func (m Model) renderTaskCard(task *models.Task, maxWidth int, isSubtask bool) s
 	if isSubtask {
 		prefix = "- "
 	}
+	if m.marked[task.ID] {
+		prefix = markMarker + prefix
+	}
 
 	// Priority indicator
 	priorityColor, ok := priorityColors[task.Priority]
 	if !ok {
-		priorityColor = gruvboxGray
+		priorityColor = colorGray
 	}
 	priorityStyle := lipgloss.NewStyle().
 		Foreground(priorityColor).
@@ -208,13 +258,20 @@ func (m Model) renderTaskCard(task *models.Task, maxWidth int, isSubtask bool) s
 
 	// Created at (short format)
 	createdStr := task.CreatedAt.Format("01/02")
-	timeStyle := lipgloss.NewStyle().Foreground(gruvboxGray)
+	timeStyle := lipgloss.NewStyle().Foreground(colorGray)
 
 	// Calculate space for title
 	// Format: "- P1 title... 01/02" (prefix + priority + title + date)
-	prefixLen := len(prefix)
+	prefixLen := lipgloss.Width(prefix)
 	reservedSpace := prefixLen + 3 + 1 + 5 + 1 // prefix + "P1 " + " " + "01/02"
-	titleMaxLen := maxWidth - reservedSpace
+
+	// Collapsed parents summarize their hidden subtasks after the title,
+	// leaving room for at least 10 characters of it
+	summary := m.collapseSummary(task, maxWidth-reservedSpace-10-1)
+	if summary != "" {
+		summary = " " + summary
+	}
+	titleMaxLen := maxWidth - reservedSpace - lipgloss.Width(summary)
 	if titleMaxLen < 10 {
 		titleMaxLen = 10
 	}
@@ -229,10 +286,11 @@ func (m Model) renderTaskCard(task *models.Task, maxWidth int, isSubtask bool) s
 	}
 
 	// Build single line: "- P1 title... 01/02"
-	return fmt.Sprintf("%s%s %s %s",
+	return fmt.Sprintf("%s%s %s%s %s",
 		prefix,
 		priorityStr,
 		title,
+		timeStyle.Render(summary),
 		timeStyle.Render(createdStr),
 	)
 }
@@ -0,0 +1,91 @@
+package tui
+
+import "testing"
+
+func TestTaskWindow(t *testing.T) {
+	m := newTestModel(t)
+	m = resize(m, 120, 30)
+	lines := m.taskLines()
+	size := lines - 2 // Room for "↑ N more" and "↓ N more"
+
+	tests := []struct {
+		name      string
+		count     int
+		offset    int
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "empty", count: 0, wantStart: 0, wantEnd: 0},
+		{name: "fits", count: lines, offset: 3, wantStart: 0, wantEnd: lines},
+		{name: "top", count: 50, wantStart: 0, wantEnd: size},
+		{name: "scrolled", count: 50, offset: 10, wantStart: 10, wantEnd: 10 + size},
+		{name: "past the end", count: 50, offset: 49, wantStart: 50 - size, wantEnd: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.columnScrollOffset[0] = tt.offset
+			start, end := m.taskWindow(0, tt.count)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("Expected %d-%d, got %d-%d", tt.wantStart, tt.wantEnd, start, end)
+			}
+		})
+	}
+}
+
+// TestScrollToSelection tests the column scrolls only once the selection
+// leaves the window, and just enough to show it
+func TestScrollToSelection(t *testing.T) {
+	m := newTestModel(t, inboxTasks(26)...)
+	m = resize(m, 120, 30)
+	size := m.windowSize(26)
+
+	// Moving within the window doesn't scroll
+	for range size - 1 {
+		m, _ = press(t, m, "j")
+	}
+	if start, _ := m.taskWindow(0, 26); start != 0 {
+		t.Fatalf("Expected no scrolling within the window, got offset %d", start)
+	}
+
+	// Moving past the bottom shows the selection on the last line
+	m, _ = press(t, m, "j")
+	if start, end := m.taskWindow(0, 26); start != 1 || end-1 != m.selectedTask {
+		t.Errorf("Expected the selection %d on the last line, got %d-%d", m.selectedTask, start, end)
+	}
+
+	// Moving past the top shows the selection on the first line
+	m.selectedTask = 0
+	m.scrollToSelection()
+	if start, _ := m.taskWindow(0, 26); start != 0 {
+		t.Errorf("Expected the column scrolled to the top, got offset %d", start)
+	}
+
+	// Jumping far shows the selection
+	m.selectedTask = 20
+	m.scrollToSelection()
+	if start, end := m.taskWindow(0, 26); m.selectedTask < start || m.selectedTask >= end {
+		t.Errorf("Expected the selection %d in view, got %d-%d", m.selectedTask, start, end)
+	}
+}
+
+// TestScrollToSelection_LaneHeader tests the first task of a swimlane is
+// scrolled into view along with the lane's header
+func TestScrollToSelection_LaneHeader(t *testing.T) {
+	m := newTestModel(t, inboxTasks(26)...) // The last 22 are all P5
+	m.swimlanes = SwimlanePriority
+	m = resize(m, 120, 30)
+
+	m.selectedTask = 25
+	m.scrollToSelection()
+	m.selectedTask = 4 // First task of the P5 lane
+	m.scrollToSelection()
+
+	lines := m.columnLines(0)
+	header := taskLine(lines, 4) - 1
+	if lines[header].lane == nil {
+		t.Fatalf("Expected a lane header before task 4, got %+v", lines[header])
+	}
+	if start, _ := m.taskWindow(0, len(lines)); start != header {
+		t.Errorf("Expected the window to start at the header on line %d, got %d", header, start)
+	}
+}
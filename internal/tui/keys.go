@@ -1,48 +1,81 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines the keybindings for the TUI
 type KeyMap struct {
-	Up            key.Binding
-	Down          key.Binding
-	Left          key.Binding
-	Right         key.Binding
-	Select        key.Binding
-	Back          key.Binding
-	Quit          key.Binding
-	Help          key.Binding
-	Move          key.Binding
-	Archive       key.Binding
-	Delete        key.Binding
-	Refresh       key.Binding
-	New           key.Binding
-	Edit          key.Binding
-	Tab           key.Binding
-	ShiftTab      key.Binding
-	Sort          key.Binding
-	ToggleArchive key.Binding
-	ToggleView    key.Binding
-	Save          key.Binding
+	Up             key.Binding
+	Down           key.Binding
+	Left           key.Binding
+	Right          key.Binding
+	Select         key.Binding
+	Back           key.Binding
+	Quit           key.Binding
+	Help           key.Binding
+	Move           key.Binding
+	Archive        key.Binding
+	Delete         key.Binding
+	Refresh        key.Binding
+	New            key.Binding
+	QuickAdd       key.Binding
+	Edit           key.Binding
+	Tab            key.Binding
+	ShiftTab       key.Binding
+	Sort           key.Binding
+	ToggleArchive  key.Binding
+	ToggleView     key.Binding
+	Board          key.Binding
+	Save           key.Binding
+	Template       key.Binding
 	QuickMoveLeft  key.Binding
 	QuickMoveRight key.Binding
 	QuickMoveUp    key.Binding
 	QuickMoveDown  key.Binding
+	Mark           key.Binding
+	MarkRange      key.Binding
+	MarkAll        key.Binding
+	Tag            key.Binding
+	Priority       key.Binding
+	Collapse       key.Binding
+	CollapseAll    key.Binding
+	ExpandAll      key.Binding
+	Focus          key.Binding
+	Filter         key.Binding
+	NextView       key.Binding
+	Palette        key.Binding
+	Swimlanes      key.Binding
+	NextLane       key.Binding
+	PreviousLane   key.Binding
+	PageUp         key.Binding
+	PageDown       key.Binding
+	Home           key.Binding
+	End            key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view
 func (k KeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit}
+	return []key.Binding{k.Palette, k.Help, k.Quit}
 }
 
 // FullHelp returns keybindings for the expanded help view
 func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},
-		{k.Select, k.Back, k.New, k.Edit},
+		{k.Up, k.Down, k.Left, k.Right, k.PageUp, k.PageDown, k.Home, k.End},
+		{k.Select, k.Back, k.New, k.QuickAdd, k.Edit},
 		{k.Move, k.Archive, k.Delete, k.Refresh},
-		{k.Sort, k.ToggleArchive, k.ToggleView, k.Help, k.Quit},
+		{k.Sort, k.Filter, k.NextView, k.ToggleArchive, k.ToggleView, k.Board, k.Palette, k.Help, k.Quit},
 		{k.QuickMoveLeft, k.QuickMoveRight, k.QuickMoveUp, k.QuickMoveDown},
+		{k.Mark, k.MarkRange, k.MarkAll, k.Tag, k.Priority},
+		{k.Collapse, k.CollapseAll, k.ExpandAll, k.Focus},
+		{k.Swimlanes, k.NextLane, k.PreviousLane},
 	}
 }
 
@@ -101,6 +134,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("n"),
 			key.WithHelp("n", "new task"),
 		),
+		QuickAdd: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "quick add"),
+		),
 		Edit: key.NewBinding(
 			key.WithKeys("e"),
 			key.WithHelp("e", "edit"),
@@ -125,6 +162,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("v"),
 			key.WithHelp("v", "toggle view layout"),
 		),
+		Board: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "switch board"),
+		),
 		QuickMoveLeft: key.NewBinding(
 			key.WithKeys("H"),
 			key.WithHelp("H", "quick move left"),
@@ -145,5 +186,255 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+s"),
 			key.WithHelp("ctrl+s", "save form"),
 		),
+		Template: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "new from template"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark task"),
+		),
+		MarkRange: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "mark range"),
+		),
+		MarkAll: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "mark column"),
+		),
+		Tag: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "edit tags"),
+		),
+		Priority: key.NewBinding(
+			key.WithKeys("1", "2", "3", "4", "5"),
+			key.WithHelp("1-5", "set priority"),
+		),
+		Collapse: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "collapse/expand (za: z taken)"),
+		),
+		CollapseAll: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "collapse all"),
+		),
+		ExpandAll: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "expand all"),
+		),
+		Focus: key.NewBinding(
+			key.WithKeys("Z"),
+			key.WithHelp("Z", "focus on task/back out"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "filter"),
+		),
+		NextView: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "next saved view"),
+		),
+		Palette: key.NewBinding(
+			key.WithKeys(":", "ctrl+p"),
+			key.WithHelp(":/ctrl+p", "command palette"),
+		),
+		Swimlanes: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "cycle swimlanes"),
+		),
+		NextLane: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next lane"),
+		),
+		PreviousLane: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous lane"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "ctrl+u"),
+			key.WithHelp("pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "ctrl+d"),
+			key.WithHelp("pgdn", "page down"),
+		),
+		Home: key.NewBinding(
+			key.WithKeys("home", "g"),
+			key.WithHelp("g/home", "first task"),
+		),
+		End: key.NewBinding(
+			key.WithKeys("end", "G"),
+			key.WithHelp("G/end", "last task"),
+		),
+	}
+}
+
+// actions returns the bindings that can be changed in the [keys] config
+// table, by action name
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", &k.Up, scopeBoard},
+		{"down", &k.Down, scopeBoard},
+		{"left", &k.Left, scopeBoard},
+		{"right", &k.Right, scopeBoard},
+		{"select", &k.Select, scopeBoard},
+		{"back", &k.Back, scopeBoth},
+		{"quit", &k.Quit, scopeBoth},
+		{"help", &k.Help, scopeBoard},
+		{"move", &k.Move, scopeBoard},
+		{"archive", &k.Archive, scopeBoard},
+		{"delete", &k.Delete, scopeBoard},
+		{"refresh", &k.Refresh, scopeBoard},
+		{"new", &k.New, scopeBoard},
+		{"quick_add", &k.QuickAdd, scopeBoard},
+		{"edit", &k.Edit, scopeBoard},
+		{"next_field", &k.Tab, scopeForm},
+		{"previous_field", &k.ShiftTab, scopeForm},
+		{"sort", &k.Sort, scopeBoard},
+		{"toggle_archive", &k.ToggleArchive, scopeBoard},
+		{"toggle_view", &k.ToggleView, scopeBoard},
+		{"board", &k.Board, scopeBoard},
+		{"save", &k.Save, scopeForm},
+		{"template", &k.Template, scopeForm},
+		{"quick_move_left", &k.QuickMoveLeft, scopeBoard},
+		{"quick_move_right", &k.QuickMoveRight, scopeBoard},
+		{"quick_move_up", &k.QuickMoveUp, scopeBoard},
+		{"quick_move_down", &k.QuickMoveDown, scopeBoard},
+		{"mark", &k.Mark, scopeBoard},
+		{"mark_range", &k.MarkRange, scopeBoard},
+		{"mark_all", &k.MarkAll, scopeBoard},
+		{"tag", &k.Tag, scopeBoard},
+		{"priority", &k.Priority, scopeBoard},
+		{"collapse", &k.Collapse, scopeBoard},
+		{"collapse_all", &k.CollapseAll, scopeBoard},
+		{"expand_all", &k.ExpandAll, scopeBoard},
+		{"focus", &k.Focus, scopeBoard},
+		{"filter", &k.Filter, scopeBoard},
+		{"next_view", &k.NextView, scopeBoard},
+		{"palette", &k.Palette, scopeBoard},
+		{"swimlanes", &k.Swimlanes, scopeBoard},
+		{"next_lane", &k.NextLane, scopeBoard},
+		{"previous_lane", &k.PreviousLane, scopeBoard},
+		{"page_up", &k.PageUp, scopeBoard},
+		{"page_down", &k.PageDown, scopeBoard},
+		{"home", &k.Home, scopeBoard},
+		{"end", &k.End, scopeBoard},
+	}
+}
+
+// keyAction is a binding that can be changed from the config
+type keyAction struct {
+	name    string
+	binding *key.Binding
+	scope   keyScope
+}
+
+// keyScope tells where an action's keys are handled. Keys only need to be
+// unique within a scope.
+type keyScope int
+
+const (
+	scopeBoard keyScope = iota // Every view but the task form
+	scopeForm                  // The task form, where other keys type text
+	scopeBoth
+)
+
+// KeyActions returns the action names accepted in the [keys] config table
+func KeyActions() []string {
+	var k KeyMap
+	var names []string
+	for _, action := range k.actions() {
+		names = append(names, action.name)
+	}
+	return names
+}
+
+// NewKeyMap returns the default keybindings with the actions in bindings
+// rebound, as read from the [keys] config table. Each action is given its
+// list of keys, replacing the default ones; priority takes five keys, for
+// P1 to P5. An empty list unbinds the action.
+//
+// Unknown actions are reported and skipped. If two actions that are active
+// at the same time share a key, the default keybindings are returned along
+// with the error.
+func NewKeyMap(bindings map[string][]string) (KeyMap, error) {
+	keys := DefaultKeyMap()
+	actions := keys.actions()
+
+	var errs []error
+	names := slices.Sorted(maps.Keys(bindings))
+	for _, name := range names {
+		i := slices.IndexFunc(actions, func(a keyAction) bool { return a.name == name })
+		if i < 0 {
+			errs = append(errs, fmt.Errorf("keys.%s: unknown action (want one of %s)", name, strings.Join(KeyActions(), ", ")))
+			continue
+		}
+		list := make([]string, len(bindings[name]))
+		for j, k := range bindings[name] {
+			list[j] = normalizeKey(k)
+		}
+		if name == "priority" && len(list) != 5 {
+			errs = append(errs, fmt.Errorf("keys.priority: want 5 keys, for P1 to P5, got %d", len(list)))
+			continue
+		}
+		binding := actions[i].binding
+		if len(list) == 0 {
+			binding.Unbind()
+			continue
+		}
+		binding.SetKeys(list...)
+		binding.SetHelp(keyHelp(list), binding.Help().Desc)
+	}
+
+	for _, scope := range []keyScope{scopeBoard, scopeForm} {
+		owner := make(map[string]string)
+		for _, action := range actions {
+			if action.scope != scope && action.scope != scopeBoth {
+				continue
+			}
+			for _, k := range action.binding.Keys() {
+				if other, ok := owner[k]; ok && other != action.name {
+					errs = append(errs, fmt.Errorf("keys: %q is bound to both %s and %s (using the default keybindings)", keyName(k), other, action.name))
+					return DefaultKeyMap(), errors.Join(errs...)
+				}
+				owner[k] = action.name
+			}
+		}
+	}
+	return keys, errors.Join(errs...)
+}
+
+// normalizeKey converts a key as written in the config to the name bubbletea
+// gives it
+func normalizeKey(k string) string {
+	if k == "space" {
+		return " "
+	}
+	return k
+}
+
+// keyName returns the name of a key to show to the user
+func keyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return k
+}
+
+// keyHelp returns the help text for a list of keys, like "k/↑"
+func keyHelp(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = keyName(k)
 	}
+	return strings.Join(names, "/")
 }
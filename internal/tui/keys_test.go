@@ -0,0 +1,155 @@
+package tui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucasefe/ontop/internal/config"
+)
+
+// TestNewKeyMap tests rebinding actions from the [keys] config table
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string][]string
+		wantErr  string // Part of the error; "" for none
+		defaults bool   // Whether the default keybindings are returned
+		check    func(KeyMap) bool
+	}{
+		{
+			name:     "rebind",
+			bindings: map[string][]string{"archive": {"x"}, "delete": {"X", "ctrl+d"}, "page_down": {"pgdown"}},
+			check: func(k KeyMap) bool {
+				return slices.Equal(k.Archive.Keys(), []string{"x"}) && slices.Equal(k.Delete.Keys(), []string{"X", "ctrl+d"}) &&
+					k.Archive.Help().Key == "x" && k.Delete.Help().Key == "X/ctrl+d"
+			},
+		},
+		{
+			name:     "space is normalised",
+			bindings: map[string][]string{"mark": {"space"}},
+			check: func(k KeyMap) bool {
+				return slices.Equal(k.Mark.Keys(), []string{" "}) && k.Mark.Help().Key == "space"
+			},
+		},
+		{
+			name:     "conflict within the board",
+			bindings: map[string][]string{"archive": {"d"}},
+			wantErr:  `"d" is bound to both`,
+			defaults: true,
+		},
+		{
+			name:     "conflict with a key shared by both scopes",
+			bindings: map[string][]string{"template": {"esc"}},
+			wantErr:  `"esc" is bound to both`,
+			defaults: true,
+		},
+		{
+			name:     "same key on the board and in the form",
+			bindings: map[string][]string{"archive": {"tab"}},
+			check: func(k KeyMap) bool {
+				return slices.Equal(k.Archive.Keys(), []string{"tab"}) && slices.Equal(k.Tab.Keys(), []string{"tab"})
+			},
+		},
+		{
+			name:     "priority needs five keys",
+			bindings: map[string][]string{"priority": {"!", "@"}, "archive": {"x"}},
+			wantErr:  "keys.priority: want 5 keys",
+			check: func(k KeyMap) bool {
+				return len(k.Priority.Keys()) == 5 && slices.Equal(k.Archive.Keys(), []string{"x"})
+			},
+		},
+		{
+			name:     "unknown action",
+			bindings: map[string][]string{"fly": {"F"}, "archive": {"x"}},
+			wantErr:  "keys.fly: unknown action",
+			check: func(k KeyMap) bool {
+				return slices.Equal(k.Archive.Keys(), []string{"x"})
+			},
+		},
+		{
+			name:     "empty list unbinds",
+			bindings: map[string][]string{"archive": {}},
+			check: func(k KeyMap) bool {
+				return len(k.Archive.Keys()) == 0 && !k.Archive.Enabled()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewKeyMap(tt.bindings)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if tt.defaults && !sameBindings(keys, DefaultKeyMap()) {
+				t.Errorf("Expected the default keybindings")
+			}
+			if tt.check != nil && !tt.check(keys) {
+				t.Errorf("Unexpected keybindings for %v", tt.bindings)
+			}
+		})
+	}
+}
+
+// sameBindings reports whether two keymaps bind every action to the same keys
+func sameBindings(a, b KeyMap) bool {
+	bActions := b.actions()
+	for i, action := range a.actions() {
+		if !slices.Equal(action.binding.Keys(), bActions[i].binding.Keys()) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestDefaultKeyMap tests the default keybindings have no conflicts
+func TestDefaultKeyMap(t *testing.T) {
+	if _, err := NewKeyMap(nil); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	var unbound []string
+	keys := DefaultKeyMap()
+	for _, action := range keys.actions() {
+		if !action.binding.Enabled() {
+			unbound = append(unbound, action.name)
+		}
+	}
+	if len(unbound) > 0 {
+		t.Errorf("Expected every action to have keys, got none for %v", unbound)
+	}
+}
+
+// TestNewKeyMap_EmptyListInConfig tests an action given no keys in the
+// config file is left without keys, rather than rejected
+func TestNewKeyMap_EmptyListInConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Chdir(t.TempDir())
+	if err := os.MkdirAll(config.GetConfigDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.GetConfigPath(), []byte("[keys]\narchive = []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	keys, err := NewKeyMap(cfg.Keys)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keys.Archive.Enabled() {
+		t.Errorf("Expected archive to be disabled, got keys %v", keys.Archive.Keys())
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, keys.Archive) {
+		t.Errorf("Expected a to no longer archive")
+	}
+}
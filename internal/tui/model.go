@@ -3,14 +3,19 @@ package tui
 import (
 	"database/sql"
 	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/lipgloss"
 	"github.com/lucasefe/ontop/internal/config"
 	"github.com/lucasefe/ontop/internal/models"
 	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
 )
 
 // ViewMode represents the current view in the TUI
@@ -23,6 +28,13 @@ const (
 	ViewModeCreate
 	ViewModeEdit
 	ViewModeDeleteConfirm
+	ViewModeConflict
+	ViewModeTagPrompt
+	ViewModeQuickAdd
+	ViewModeTemplate
+	ViewModeBoardPicker
+	ViewModeFilter
+	ViewModePalette
 )
 
 // ViewLayout represents the visual organization of the kanban board
@@ -30,7 +42,7 @@ type ViewLayout int
 
 const (
 	LayoutColumn ViewLayout = iota // Vertical columns (existing)
-	LayoutRow                       // Horizontal rows (new)
+	LayoutRow                      // Horizontal rows (new)
 )
 
 // SortMode is an alias to service.SortMode for backward compatibility
@@ -46,27 +58,79 @@ const (
 
 // Model represents the Bubbletea application state
 type Model struct {
-	db              *sql.DB
-	tasks           []*models.Task
-	currentColumn   int // 0=inbox, 1=in_progress, 2=done
-	selectedTask    int // Index within current column
-	viewMode        ViewMode
-	viewLayout      ViewLayout     // Layout mode for Kanban view (column or row)
-	sortMode        SortMode       // How tasks are sorted in columns
-	showArchived    bool           // Show archived tasks instead of active
-	rowScrollOffset map[int]int    // Horizontal scroll offsets per row (row mode only)
-	detailTask      *models.Task
-	detailSubtasks  []*models.Task
-	moveTask        *models.Task
-	moveSelection   int          // Which column to move to
-	deleteTask      *models.Task // Task pending deletion
-	lastMovedTaskID string       // Track moved task to restore focus
+	db                 *sql.DB
+	svc                *service.TaskService
+	allTasks           []*models.Task // Tasks loaded from the database
+	tasks              []*models.Task // The loaded tasks matching the filter
+	currentColumn      int            // 0=inbox, 1=in_progress, 2=done
+	selectedTask       int            // Index within current column
+	viewMode           ViewMode
+	viewLayout         ViewLayout   // Layout mode for Kanban view (column or row)
+	sortMode           SortMode     // How tasks are sorted in columns
+	swimlanes          SwimlaneMode // Dimension the board is split into lanes by
+	showArchived       bool         // Show archived tasks instead of active
+	boardID            string       // Board whose tasks are shown
+	boardName          string
+	dbPath             string      // Database file, shown in the status bar
+	dbFile             string      // Database file, whose session state is used
+	rowScrollOffset    map[int]int // First visible task per row (row mode only)
+	columnScrollOffset map[int]int // First visible task per column (column mode only)
+	detailTask         *models.Task
+	detailSubtasks     []*models.Task
+	moveTask           *models.Task
+	moveSelection      int             // Which column to move to
+	deleteTask         *models.Task    // Task pending deletion
+	lastMovedTaskID    string          // Track moved task to restore focus
+	rememberedID       string          // Last selection saved for @selected
+	selectionID        string          // Selection after the last key or mouse event
+	selectionSeq       int             // Counts selection changes, to save the one that settles
+	collapsed          map[string]bool // Parent task IDs whose subtasks are hidden
+	focus              *models.Task    // Task zoomed into, showing only its subtasks (nil: whole board)
+	// Multi-select
+	marked        map[string]bool         // IDs of marked tasks
+	markAnchor    string                  // Last task marked with space, start of a V range
+	bulkIDs       []string                // Tasks the open move/delete/tag prompt applies to (nil: single task)
+	tagInput      textinput.Model         // Tag prompt input
+	quickAddInput textinput.Model         // Quick-add prompt input
+	changes       *storage.ChangeDetector // Detects writes from other processes (nil if unavailable)
+	// Filter bar
+	filter       *service.Filter // Applied to allTasks to get tasks (nil: no filter)
+	filterExpr   string
+	filterInput  textinput.Model
+	filterBefore string // Filter when the bar was opened, restored on cancel
+	savingView   bool   // Naming the view the filter is saved as
+	// Saved views
+	view       *models.View // Saved view in use, cycled with F (nil: none)
+	viewBefore viewState    // Filter, sort and layout to restore when leaving the views
+	// Command palette
+	paletteInput     textinput.Model
+	paletteCommands  []paletteCommand // Every command, listed when the palette opened
+	paletteMatches   []paletteCommand // Commands matching the input, best first
+	paletteSelection int
 	// Form fields
 	formInputs     []textinput.Model
 	formTextarea   textarea.Model // For multiline description
 	formFocusIndex int
 	formTask       *models.Task // Task being created/edited
 	formErr        error        // Form validation error (doesn't quit app)
+	// Template picker (opened from the create form)
+	templateNames     []string
+	templateSelection int
+	template          *service.Template // Picked template, nil while choosing
+	templateInputs    []textinput.Model // One per template variable
+	templateFocus     int
+	// Board picker
+	boards         []*models.Board
+	boardCounts    map[string]int // Active tasks per board ID
+	boardSelection int
+	// Edit conflict resolution
+	conflictTask      *models.Task // Latest stored version of the task being edited
+	conflictEdit      *models.Task // The local edit that failed to save
+	conflictSelection int          // 0=reload, 1=overwrite, 2=merge
+	// Mouse
+	lastClick   time.Time // When lastClickID was clicked, to detect double-clicks
+	lastClickID string
+	dragTaskID  string // Task pressed on, moved if released over another column
 	// UI components
 	keys          KeyMap
 	help          help.Model
@@ -78,22 +142,26 @@ type Model struct {
 
 // NewModel creates a new TUI model
 func NewModel(db *sql.DB) Model {
-	h := help.New()
-	// Apply Gruvbox theme colors to help
-	gruvboxGreen := "#b8bb26"
-	gruvboxGray := "#928374"
-	h.Styles.ShortKey = h.Styles.ShortKey.Foreground(lipgloss.Color(gruvboxGreen))
-	h.Styles.ShortDesc = h.Styles.ShortDesc.Foreground(lipgloss.Color(gruvboxGray))
-	h.Styles.FullKey = h.Styles.FullKey.Foreground(lipgloss.Color(gruvboxGreen))
-	h.Styles.FullDesc = h.Styles.FullDesc.Foreground(lipgloss.Color(gruvboxGray))
-	h.Styles.ShortSeparator = h.Styles.ShortSeparator.Foreground(lipgloss.Color(gruvboxGray))
-	h.Styles.FullSeparator = h.Styles.FullSeparator.Foreground(lipgloss.Color(gruvboxGray))
-
 	// Load config to get view layout preference
 	cfg, err := config.Load()
 	if err != nil {
-		log.Printf("Warning: Failed to load config: %v (using defaults)", err)
+		log.Printf("Warning: Failed to load config: %v (ignoring invalid settings)", err)
+	}
+
+	theme, err := LoadTheme(cfg.UI.Theme)
+	if err != nil {
+		log.Printf("Warning: %v (using the %s theme)", err, theme.Name)
 	}
+	applyTheme(theme)
+
+	// Apply the theme colors to help
+	h := help.New()
+	h.Styles.ShortKey = h.Styles.ShortKey.Foreground(colorGreen)
+	h.Styles.ShortDesc = h.Styles.ShortDesc.Foreground(colorGray)
+	h.Styles.FullKey = h.Styles.FullKey.Foreground(colorGreen)
+	h.Styles.FullDesc = h.Styles.FullDesc.Foreground(colorGray)
+	h.Styles.ShortSeparator = h.Styles.ShortSeparator.Foreground(colorGray)
+	h.Styles.FullSeparator = h.Styles.FullSeparator.Foreground(colorGray)
 
 	// Parse view mode from config
 	viewLayout := LayoutColumn // Default
@@ -101,37 +169,111 @@ func NewModel(db *sql.DB) Model {
 		viewLayout = LayoutRow
 	}
 
+	sortMode := SortByPriority
+	if i := slices.Index(config.SortModes, cfg.UI.Sort); i >= 0 {
+		sortMode = SortMode(i)
+	}
+
+	swimlanes := SwimlaneNone
+	if i := slices.Index(config.SwimlaneModes, cfg.UI.Swimlanes); i >= 0 {
+		swimlanes = SwimlaneMode(i)
+	}
+
+	// Apply the [keys] config table; conflicts fall back to the defaults
+	keys, err := NewKeyMap(cfg.Keys)
+	status := ""
+	if err != nil {
+		log.Printf("Warning: Invalid keybindings: %v", err)
+		status = "Invalid keybindings: " + strings.ReplaceAll(err.Error(), "\n", "; ")
+	}
+
+	// Show the board given with --board or used last, falling back to the
+	// default board if it no longer exists
+	board, err := service.NewBoardService(db).Get(cfg.Board)
+	if err != nil {
+		log.Printf("Warning: Failed to load board: %v (using default)", err)
+		board = &models.Board{ID: models.DefaultBoardID, Name: models.DefaultBoardID}
+	}
+
+	dbPath, err := storage.DBPath(db)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Collapse the parents collapsed in the last session
+	collapsed := make(map[string]bool)
+	if state, err := config.LoadState(dbPath); err == nil {
+		for _, id := range state.Collapsed {
+			collapsed[id] = true
+		}
+	}
+
+	// Watch for writes from other processes (CLI, API server) to live-refresh
+	changes, err := storage.NewChangeDetector(db)
+	if err != nil {
+		log.Printf("Warning: Live refresh disabled: %v", err)
+	}
+
 	return Model{
-		db:              db,
-		currentColumn:   0,
-		selectedTask:    0,
-		viewMode:        ViewModeKanban,
-		viewLayout:      viewLayout,
-		sortMode:        SortByPriority,
-		rowScrollOffset: make(map[int]int),
-		keys:            DefaultKeyMap(),
-		help:            h,
-		width:           80,
-		height:          24,
+		db:                 db,
+		svc:                service.NewTaskService(db),
+		changes:            changes,
+		currentColumn:      0,
+		selectedTask:       0,
+		viewMode:           ViewModeKanban,
+		viewLayout:         viewLayout,
+		sortMode:           sortMode,
+		swimlanes:          swimlanes,
+		showArchived:       cfg.UI.ShowArchived,
+		boardID:            board.ID,
+		boardName:          board.Name,
+		dbPath:             shortenPath(dbPath),
+		dbFile:             dbPath,
+		rowScrollOffset:    make(map[int]int),
+		columnScrollOffset: make(map[int]int),
+		marked:             make(map[string]bool),
+		collapsed:          collapsed,
+		keys:               keys,
+		statusMessage:      status,
+		help:               h,
+		width:              80,
+		height:             24,
 	}
 }
 
-// GetTasksByColumn returns tasks filtered by column in hierarchical display order
+// GetTasksByColumn returns tasks filtered by column in hierarchical display
+// order. While focused on a task, only its subtasks are returned. With
+// swimlanes, the tasks are grouped by lane, in lane order.
 func (m *Model) GetTasksByColumn(column string) []*models.Task {
+	if m.swimlanes != SwimlaneNone {
+		var result []*models.Task
+		for _, l := range m.lanes() {
+			result = append(result, l.tasks[column]...)
+		}
+		return result
+	}
+
 	var filtered []*models.Task
 	for _, task := range m.tasks {
-		if task.Column == column {
+		if task.Column == column && m.inFocus(task) {
 			filtered = append(filtered, task)
 		}
 	}
+	return m.hierarchy(filtered)
+}
 
-	// Build hierarchical display order
-	hierarchical := service.BuildFlatHierarchy(filtered, m.sortMode)
+// hierarchy returns tasks in hierarchical display order
+func (m *Model) hierarchy(tasks []*models.Task) []*models.Task {
+	hierarchical := service.BuildFlatHierarchy(tasks, m.sortMode)
 
-	// Extract Task pointers (unwrap HierarchicalTask)
-	result := make([]*models.Task, len(hierarchical))
-	for i, ht := range hierarchical {
-		result[i] = ht.Task
+	// Extract Task pointers (unwrap HierarchicalTask), leaving out the
+	// subtasks of collapsed parents
+	result := make([]*models.Task, 0, len(hierarchical))
+	for _, ht := range hierarchical {
+		if ht.IsSubtask && m.collapsed[*ht.Task.ParentID] {
+			continue
+		}
+		result = append(result, ht.Task)
 	}
 
 	return result
@@ -155,6 +297,38 @@ func (m *Model) GetSelectedTask() *models.Task {
 	return nil
 }
 
+// restoreSelection selects the task with the given ID, preferring the current
+// column and following the task if it moved to another column. If the task
+// is gone, the selection index is clamped to the current column.
+func (m *Model) restoreSelection(taskID string) {
+	if taskID != "" {
+		columns := models.ValidColumns()
+		order := []int{m.currentColumn}
+		for i := range columns {
+			if i != m.currentColumn {
+				order = append(order, i)
+			}
+		}
+		for _, col := range order {
+			for i, task := range m.GetTasksByColumn(columns[col]) {
+				if task.ID == taskID {
+					m.currentColumn = col
+					m.selectedTask = i
+					return
+				}
+			}
+		}
+	}
+
+	columnTasks := m.GetTasksByColumn(m.GetCurrentColumnName())
+	if m.selectedTask >= len(columnTasks) {
+		m.selectedTask = len(columnTasks) - 1
+	}
+	if m.selectedTask < 0 {
+		m.selectedTask = 0
+	}
+}
+
 // GetSortModeName returns the display name for the current sort mode
 func (m *Model) GetSortModeName() string {
 	switch m.sortMode {
@@ -177,3 +351,15 @@ func (m *Model) ToggleSortMode() {
 	// Reset selection to top when sort changes
 	m.selectedTask = 0
 }
+
+// shortenPath abbreviates the home directory in path to ~
+func shortenPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
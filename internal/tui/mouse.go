@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// boardTop is the number of lines above the board's columns (or rows): the
+// title and a blank line
+const boardTop = 2
+
+// doubleClickInterval is the longest time between two clicks on a card for
+// them to open it
+const doubleClickInterval = 400 * time.Millisecond
+
+// boardHit is the part of the board under the mouse pointer
+type boardHit struct {
+	column int // Column index, or row index in row layout
+	task   int // Index of the task in the column; -1 if not over a card
+}
+
+// handleMouse handles mouse events on the board: click to select, double-click
+// to open, wheel to scroll and drag a card onto another column to move it
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.viewMode != ViewModeKanban {
+		return m, nil
+	}
+	hit, onBoard := m.hitTest(msg.X, msg.Y)
+
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp && msg.Action == tea.MouseActionPress:
+		if onBoard {
+			m.scroll(hit.column, -1)
+		}
+
+	case msg.Button == tea.MouseButtonWheelDown && msg.Action == tea.MouseActionPress:
+		if onBoard {
+			m.scroll(hit.column, 1)
+		}
+
+	case msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress:
+		m.dragTaskID = ""
+		if !onBoard {
+			return m, nil
+		}
+		if hit.task < 0 {
+			if hit.column != m.currentColumn {
+				m.currentColumn = hit.column
+				m.selectedTask = 0
+			}
+			return m, nil
+		}
+
+		m.currentColumn = hit.column
+		m.selectedTask = hit.task
+		task := m.GetSelectedTask()
+		if task == nil {
+			return m, nil
+		}
+		if task.ID == m.lastClickID && time.Since(m.lastClick) <= doubleClickInterval {
+			m.lastClickID = ""
+			return m.openDetail(task), nil
+		}
+		m.lastClick = time.Now()
+		m.lastClickID = task.ID
+		m.dragTaskID = task.ID
+
+	case msg.Action == tea.MouseActionMotion && m.dragTaskID != "":
+		m.statusMessage = ""
+		if onBoard && hit.column != m.currentColumn {
+			m.statusMessage = "Release to move to " + formatColumnName(models.ValidColumns()[hit.column])
+		}
+
+	case msg.Action == tea.MouseActionRelease && m.dragTaskID != "":
+		id := m.dragTaskID
+		m.dragTaskID = ""
+		m.statusMessage = ""
+		if onBoard && hit.column != m.currentColumn {
+			return m.dropTask(id, hit.column)
+		}
+	}
+	return m, nil
+}
+
+// dropTask moves a dragged task to the column at index target, along with
+// the other marked tasks if it is marked
+func (m Model) dropTask(id string, target int) (tea.Model, tea.Cmd) {
+	task := m.findTask(id)
+	if task == nil {
+		return m, nil
+	}
+	m.moveSelection = target
+	if m.marked[id] {
+		m.bulkIDs = m.markedIDs()
+	} else {
+		m.moveTask = task
+	}
+	return m.confirmMove()
+}
+
+// scroll scrolls a column by delta lines, keeping the selection in view
+func (m *Model) scroll(column, delta int) {
+	start, _ := m.taskWindow(column, len(m.columnLines(column)))
+	m.setScrollOffset(column, max(start+delta, 0))
+
+	// Move the selection onto the nearest card in view
+	lines := m.columnLines(m.currentColumn)
+	start, end := m.taskWindow(m.currentColumn, len(lines))
+	selected := taskLine(lines, m.selectedTask)
+	if selected >= start && selected < end {
+		return
+	}
+	var visible []int
+	for _, line := range lines[start:end] {
+		if line.task >= 0 {
+			visible = append(visible, line.task)
+		}
+	}
+	if len(visible) == 0 {
+		return
+	}
+	if selected < start {
+		m.selectedTask = visible[0]
+	} else {
+		m.selectedTask = visible[len(visible)-1]
+	}
+}
+
+// hitTest finds the column (or row) and task card at screen position x, y.
+// Returns false if the position is outside the board.
+func (m Model) hitTest(x, y int) (boardHit, bool) {
+	columns := models.ValidColumns()
+	if y < boardTop {
+		return boardHit{}, false
+	}
+
+	// Rows are stacked, each as tall as it renders
+	if m.viewLayout == LayoutRow {
+		top := boardTop
+		for i, column := range columns {
+			tasks := m.GetTasksByColumn(column)
+			height := lipgloss.Height(m.renderRow(columnTitles[i], tasks, i))
+			if y < top+height {
+				return boardHit{column: i, task: m.cardAt(i, y-top)}, true
+			}
+			top += height
+		}
+		return boardHit{}, false
+	}
+
+	// Columns sit side by side, as tall as the tallest one
+	i := x / (m.columnWidth() + 2) // Plus borders
+	if x < 0 || i >= len(columns) {
+		return boardHit{}, false
+	}
+	height := 0
+	for j, column := range columns {
+		height = max(height, lipgloss.Height(m.renderColumn(columnTitles[j], m.GetTasksByColumn(column), j)))
+	}
+	if y >= boardTop+height {
+		return boardHit{}, false
+	}
+	return boardHit{column: i, task: m.cardAt(i, y-boardTop)}, true
+}
+
+// cardAt returns the index of the task whose card is on line y of a rendered
+// column (or row), or -1 if there is none. Cards are one line each.
+func (m Model) cardAt(column, y int) int {
+	lines := m.columnLines(column)
+	start, end := m.taskWindow(column, len(lines))
+	line := y - 3 // Top border, header and the blank line after it
+	if start > 0 {
+		line-- // The "↑ N more" line
+	}
+	if line < 0 || start+line >= end {
+		return -1
+	}
+	return lines[start+line].task
+}
@@ -0,0 +1,131 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// resize sends a window size to m
+func resize(m Model, width, height int) Model {
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(Model)
+}
+
+// boardTasks returns tasks for every column, perColumn each, titled by
+// column and position so that no title contains another
+func boardTasks(perColumn int) []*models.Task {
+	var tasks []*models.Task
+	for i, column := range models.ValidColumns() {
+		for j := range perColumn {
+			tasks = append(tasks, &models.Task{
+				Title:    fmt.Sprintf("card-%d-%02d", i, j),
+				Priority: min(j+1, 5),
+				Column:   column,
+			})
+		}
+	}
+	return tasks
+}
+
+// findOnScreen returns the screen position of text in the rendered view
+func findOnScreen(t *testing.T, view, text string) (x, y int, ok bool) {
+	t.Helper()
+	for y, line := range strings.Split(view, "\n") {
+		if i := strings.Index(line, text); i >= 0 {
+			return len([]rune(line[:i])), y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// TestHitTest tests that every card shown on the board is hit where it is
+// rendered, in both layouts and with columns scrolled
+func TestHitTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		layout    ViewLayout
+		perColumn int
+		height    int
+	}{
+		{name: "columns", layout: LayoutColumn, perColumn: 3, height: 40},
+		{name: "scrolled columns", layout: LayoutColumn, perColumn: 20, height: 20},
+		{name: "rows", layout: LayoutRow, perColumn: 3, height: 60},
+		{name: "scrolled rows", layout: LayoutRow, perColumn: 20, height: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, boardTasks(tt.perColumn)...)
+			m.setLayout(tt.layout)
+			m = resize(m, 120, tt.height)
+
+			// Scroll the first column past its top, to show "↑ N more"
+			for range tt.perColumn {
+				m, _ = press(t, m, "j")
+			}
+			view := m.View()
+
+			shown := 0
+			for i, column := range models.ValidColumns() {
+				for j, task := range m.GetTasksByColumn(column) {
+					x, y, ok := findOnScreen(t, view, task.Title)
+					if !ok {
+						continue // Scrolled out of view
+					}
+					shown++
+					hit, onBoard := m.hitTest(x, y)
+					if !onBoard || hit.column != i || hit.task != j {
+						t.Errorf("Expected %s at %d,%d to hit column %d task %d, got %+v (on board: %v)", task.Title, x, y, i, j, hit, onBoard)
+					}
+				}
+
+				// The header isn't a card
+				x, y, ok := findOnScreen(t, view, columnTitles[i]+" (")
+				if !ok {
+					t.Fatalf("Expected the %s header in the view", columnTitles[i])
+				}
+				if hit, onBoard := m.hitTest(x, y); !onBoard || hit.column != i || hit.task != -1 {
+					t.Errorf("Expected the %s header to hit column %d and no card, got %+v (on board: %v)", columnTitles[i], i, hit, onBoard)
+				}
+			}
+			if shown == 0 {
+				t.Fatal("Expected cards in the view")
+			}
+
+			if _, onBoard := m.hitTest(5, 0); onBoard {
+				t.Error("Expected the title not to be on the board")
+			}
+			_, status, _ := findOnScreen(t, view, "DB: ")
+			if _, onBoard := m.hitTest(5, status); onBoard {
+				t.Error("Expected the status bar not to be on the board")
+			}
+		})
+	}
+}
+
+// TestCardAt_MoreLines tests the scroll indicators aren't taken for cards
+func TestCardAt_MoreLines(t *testing.T) {
+	m := newTestModel(t, boardTasks(20)...)
+	m = resize(m, 120, 20)
+	for range 10 {
+		m, _ = press(t, m, "j")
+	}
+	start, end := m.taskWindow(0, 20)
+	if start == 0 || end == 20 {
+		t.Fatalf("Expected the column scrolled from both ends, got %d-%d", start, end)
+	}
+
+	// Top border, header and blank line, then "↑ N more"
+	if got := m.cardAt(0, 3); got != -1 {
+		t.Errorf("Expected no card on the ↑ line, got %d", got)
+	}
+	if got := m.cardAt(0, 4); got != start {
+		t.Errorf("Expected task %d under the ↑ line, got %d", start, got)
+	}
+	if got := m.cardAt(0, 4+end-start); got != -1 {
+		t.Errorf("Expected no card on the ↓ line, got %d", got)
+	}
+}
@@ -8,51 +8,40 @@ import (
 	"github.com/lucasefe/ontop/internal/models"
 )
 
-var (
-	movePromptStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(gruvboxGreen).
-			Padding(1, 2).
-			MarginTop(2).
-			MarginBottom(2)
-
-	moveOptionStyle = lipgloss.NewStyle().
-			Padding(0, 2).
-			MarginRight(2)
-
-	moveSelectedStyle = lipgloss.NewStyle().
-				Background(gruvboxGreen).
-				Foreground(gruvboxBg0).
-				Bold(true).
-				Padding(0, 2).
-				MarginRight(2)
-)
-
 // renderMovePrompt renders the move task prompt
 func (m Model) renderMovePrompt() string {
-	if m.moveTask == nil {
+	bulk := len(m.bulkIDs) > 0
+	if m.moveTask == nil && !bulk {
 		return "No task selected"
 	}
 
 	var b strings.Builder
 
 	// Title
+	titleText := "Move Task"
+	if bulk {
+		titleText = fmt.Sprintf("Move %d Tasks", len(m.bulkIDs))
+	}
 	title := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(gruvboxGreen).
-		Render("Move Task")
+		Foreground(colorGreen).
+		Render(titleText)
 	b.WriteString(title + "\n\n")
 
-	// Task info
-	taskInfo := lipgloss.NewStyle().
-		Foreground(gruvboxGray).
-		Render(fmt.Sprintf("Task: %s", m.moveTask.Description))
-	b.WriteString(taskInfo + "\n")
-
-	currentColumn := lipgloss.NewStyle().
-		Foreground(gruvboxGray).
-		Render(fmt.Sprintf("Current: %s", formatColumnName(m.moveTask.Column)))
-	b.WriteString(currentColumn + "\n\n")
+	if bulk {
+		b.WriteString(m.renderBulkTargets() + "\n")
+	} else {
+		// Task info
+		taskInfo := lipgloss.NewStyle().
+			Foreground(colorGray).
+			Render(fmt.Sprintf("Task: %s", m.moveTask.Description))
+		b.WriteString(taskInfo + "\n")
+
+		currentColumn := lipgloss.NewStyle().
+			Foreground(colorGray).
+			Render(fmt.Sprintf("Current: %s", formatColumnName(m.moveTask.Column)))
+		b.WriteString(currentColumn + "\n\n")
+	}
 
 	// Column selection prompt
 	var prompt strings.Builder
@@ -72,9 +61,9 @@ func (m Model) renderMovePrompt() string {
 	prompt.WriteString("\n\n")
 
 	// Show if moving to same column
-	if columnValues[m.moveSelection] == m.moveTask.Column {
+	if !bulk && columnValues[m.moveSelection] == m.moveTask.Column {
 		sameColumnMsg := lipgloss.NewStyle().
-			Foreground(gruvboxYellow).
+			Foreground(colorYellow).
 			Render("(already in this column)")
 		prompt.WriteString(sameColumnMsg + "\n\n")
 	}
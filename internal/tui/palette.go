@@ -0,0 +1,328 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// paletteRows is the number of commands shown at once in the palette
+const paletteRows = 12
+
+// paletteWidth is the width of a command line in the palette: its title and
+// keybinding
+const paletteWidth = 56
+
+// paletteCommand is an action that can be run from the command palette
+type paletteCommand struct {
+	title string
+	keys  string // Keybinding shown next to the title; "" if it has none
+	run   func(Model) (tea.Model, tea.Cmd)
+}
+
+// openPalette opens the command palette, listing every action of the board
+func (m Model) openPalette() (Model, tea.Cmd) {
+	m.paletteInput = textinput.New()
+	m.paletteInput.Prompt = ": "
+	m.paletteInput.Placeholder = "Type a command, like 'move done' or 'p1'"
+	m.paletteInput.CharLimit = 100
+	m.paletteInput.Width = paletteWidth - 2
+	m.paletteInput.Focus()
+	m.paletteCommands = m.commands()
+	m.paletteMatches = m.paletteCommands
+	m.paletteSelection = 0
+	m.viewMode = ViewModePalette
+	return m, textinput.Blink
+}
+
+// handlePaletteKeys handles key presses in the command palette. Typing
+// narrows the commands down; enter runs the selected one.
+func (m Model) handlePaletteKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		m.viewMode = ViewModeKanban
+		return m, nil
+
+	case msg.Type == tea.KeyUp, msg.Type == tea.KeyCtrlP:
+		if m.paletteSelection > 0 {
+			m.paletteSelection--
+		}
+		return m, nil
+
+	case msg.Type == tea.KeyDown, msg.Type == tea.KeyCtrlN:
+		if m.paletteSelection < len(m.paletteMatches)-1 {
+			m.paletteSelection++
+		}
+		return m, nil
+
+	case key.Matches(msg, keys.Select):
+		if len(m.paletteMatches) == 0 {
+			return m, nil
+		}
+		command := m.paletteMatches[m.paletteSelection]
+		m.viewMode = ViewModeKanban
+		m.statusMessage = ""
+		return command.run(m)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteMatches = matchCommands(m.paletteCommands, m.paletteInput.Value())
+	m.paletteSelection = 0
+	return m, cmd
+}
+
+// commands returns the commands of the palette, grouped like the help
+func (m Model) commands() []paletteCommand {
+	commands := []paletteCommand{
+		m.actionCommand("Open task", "select", Model.openSelected),
+		m.actionCommand("New task", "new", Model.openCreateForm),
+		m.actionCommand("Quick add task", "quick_add", boardAction(Model.openQuickAdd)),
+		m.actionCommand("Move task to...", "move", Model.openMovePrompt),
+	}
+	for i, column := range models.ValidColumns() {
+		commands = append(commands, paletteCommand{
+			title: "Move to " + formatColumnName(column),
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.quickMove(i) },
+		})
+	}
+	for i := range 5 {
+		commands = append(commands, paletteCommand{
+			title: fmt.Sprintf("Set priority P%d", i+1),
+			keys:  m.actionKey("priority", i),
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.setPriority(i + 1) },
+		})
+	}
+	archive := "Archive task"
+	if m.showArchived {
+		archive = "Unarchive task"
+	}
+	focus := "Focus on task"
+	if m.focus != nil {
+		focus = "Leave focus"
+	}
+	commands = append(commands,
+		m.actionCommand("Add or remove tags", "tag", boardAction(Model.openTagPrompt)),
+		m.actionCommand(archive, "archive", Model.archiveTasks),
+		m.actionCommand("Delete task", "delete", Model.confirmDelete),
+		m.actionCommand("Mark task", "mark", boardChange((*Model).toggleMark)),
+		m.actionCommand("Mark range", "mark_range", boardChange((*Model).markRange)),
+		m.actionCommand("Mark all in column", "mark_all", boardChange((*Model).toggleMarkColumn)),
+		m.actionCommand("Collapse/expand subtasks", "collapse", boardAction(Model.toggleCollapse)),
+		m.actionCommand("Collapse all subtasks", "collapse_all", boardAction(Model.collapseAll)),
+		m.actionCommand("Expand all subtasks", "expand_all", boardAction(Model.expandAll)),
+		m.actionCommand(focus, "focus", Model.toggleFocus),
+		m.actionCommand("Filter tasks", "filter", boardAction(Model.openFilterBar)),
+		m.actionCommand("Next saved view", "next_view", Model.nextView),
+	)
+
+	views, err := service.NewViewService(m.db).List()
+	if err != nil {
+		log.Printf("Warning: Failed to list views for the palette: %v", err)
+	}
+	for _, view := range views {
+		commands = append(commands, paletteCommand{
+			title: "Use view " + view.Name,
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.useView(view), nil },
+		})
+	}
+	if m.view != nil {
+		commands = append(commands, paletteCommand{
+			title: "Leave saved view",
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				m.leaveView()
+				return m, nil
+			},
+		})
+	}
+
+	archived := "Show archived tasks"
+	if m.showArchived {
+		archived = "Show active tasks"
+	}
+	commands = append(commands,
+		m.actionCommand("Cycle sort order", "sort", Model.cycleSort),
+		m.actionCommand("Toggle layout (column/row)", "toggle_view", boardAction(Model.handleToggleView)),
+		m.actionCommand("Cycle swimlanes", "swimlanes", Model.cycleSwimlanes),
+		m.actionCommand("Next lane", "next_lane", boardChange(func(m *Model) { m.jumpLane(1) })),
+		m.actionCommand("Previous lane", "previous_lane", boardChange(func(m *Model) { m.jumpLane(-1) })),
+		m.actionCommand(archived, "toggle_archive", Model.toggleArchived),
+		m.actionCommand("Switch board...", "board", boardAction(Model.openBoardPicker)),
+	)
+
+	boards, err := service.NewBoardService(m.db).List()
+	if err != nil {
+		log.Printf("Warning: Failed to list boards for the palette: %v", err)
+	}
+	for _, board := range boards {
+		if board.ID == m.boardID {
+			continue
+		}
+		commands = append(commands, paletteCommand{
+			title: "Switch to board " + board.Name,
+			run:   func(m Model) (tea.Model, tea.Cmd) { return m.switchBoard(board) },
+		})
+	}
+
+	return append(commands,
+		m.actionCommand("Refresh", "refresh", Model.refresh),
+		paletteCommand{
+			title: "Toggle help",
+			keys:  m.actionKey("help", -1),
+			run: func(m Model) (tea.Model, tea.Cmd) {
+				m.help.ShowAll = !m.help.ShowAll
+				return m, nil
+			},
+		},
+		paletteCommand{
+			title: "Quit",
+			keys:  m.actionKey("quit", -1),
+			run:   Model.quit,
+		},
+	)
+}
+
+// actionCommand returns a command running a board action, shown with the
+// keys of the action with the given [keys] config name. The action runs
+// the same code as its keys, even if it was left without keys in the config.
+func (m Model) actionCommand(title, name string, run func(Model) (tea.Model, tea.Cmd)) paletteCommand {
+	return paletteCommand{
+		title: title,
+		keys:  m.actionKey(name, -1),
+		run:   run,
+	}
+}
+
+// boardAction adapts a board action that returns the model as a Model to
+// run from the palette
+func boardAction(action func(Model) (Model, tea.Cmd)) func(Model) (tea.Model, tea.Cmd) {
+	return func(m Model) (tea.Model, tea.Cmd) { return action(m) }
+}
+
+// boardChange adapts a board action that only changes the model to run from
+// the palette
+func boardChange(change func(*Model)) func(Model) (tea.Model, tea.Cmd) {
+	return func(m Model) (tea.Model, tea.Cmd) {
+		change(&m)
+		return m, nil
+	}
+}
+
+// actionKey returns the name of the i-th key of an action, or of all its
+// keys if i is negative
+func (m Model) actionKey(name string, i int) string {
+	for _, action := range m.keys.actions() {
+		if action.name != name {
+			continue
+		}
+		keys := action.binding.Keys()
+		if i < 0 {
+			return keyHelp(keys)
+		}
+		if i < len(keys) {
+			return keyName(keys[i])
+		}
+	}
+	return ""
+}
+
+// matchCommands returns the commands whose title fuzzy matches query, best
+// match first. Spaces in the query are ignored.
+func matchCommands(commands []paletteCommand, query string) []paletteCommand {
+	query = strings.ReplaceAll(query, " ", "")
+	if query == "" {
+		return commands
+	}
+
+	type match struct {
+		command paletteCommand
+		score   int
+	}
+	var matches []match
+	for _, command := range commands {
+		if score, ok := fuzzyScore(query, command.title); ok {
+			matches = append(matches, match{command, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return b.score - a.score })
+
+	result := make([]paletteCommand, len(matches))
+	for i, match := range matches {
+		result[i] = match.command
+	}
+	return result
+}
+
+// fuzzyScore reports whether the characters of query appear in text in
+// order, ignoring case, and scores the match: characters at the start of a
+// word or right after the previous match score higher
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(text))
+	score, next, last := 0, 0, -2
+	for i := 0; i < len(t) && next < len(q); i++ {
+		if t[i] != q[next] {
+			continue
+		}
+		score++
+		switch {
+		case i == last+1:
+			score += 3
+		case i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]):
+			score += 2
+		}
+		last = i
+		next++
+	}
+	return score, next == len(q)
+}
+
+// renderPalette renders the command palette: the query over the matching
+// commands, with their keybindings
+func (m Model) renderPalette() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGreen).
+		Render("Command Palette")
+	b.WriteString(title + "\n\n")
+
+	var prompt strings.Builder
+	prompt.WriteString(m.paletteInput.View())
+	prompt.WriteString("\n\n")
+
+	if len(m.paletteMatches) == 0 {
+		prompt.WriteString(formHelpStyle.Render("No matching commands") + "\n")
+	}
+	start := max(min(m.paletteSelection-paletteRows/2, len(m.paletteMatches)-paletteRows), 0)
+	end := min(start+paletteRows, len(m.paletteMatches))
+	keyStyle := lipgloss.NewStyle().Foreground(colorGray)
+	for i := start; i < end; i++ {
+		command := m.paletteMatches[i]
+		gap := max(paletteWidth-2-lipgloss.Width(command.title)-lipgloss.Width(command.keys), 1)
+		if i == m.paletteSelection {
+			line := "> " + command.title + strings.Repeat(" ", gap) + command.keys
+			prompt.WriteString(selectedTaskStyle.Render(line) + "\n")
+		} else {
+			prompt.WriteString(taskStyle.Render("  "+command.title+strings.Repeat(" ", gap)+keyStyle.Render(command.keys)) + "\n")
+		}
+	}
+	if len(m.paletteMatches) > paletteRows {
+		prompt.WriteString(formHelpStyle.Render(fmt.Sprintf("%d-%d of %d", start+1, end, len(m.paletteMatches))) + "\n")
+	}
+	prompt.WriteString("\n")
+	prompt.WriteString(formHelpStyle.Render("↑/↓: select • enter: run • esc: cancel"))
+
+	b.WriteString(paletteStyle.Render(prompt.String()))
+	return b.String()
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// typeText types text into m one key at a time
+func typeText(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		m, _ = press(t, m, string(r))
+	}
+	return m
+}
+
+// TestPalette_UnboundAction tests the palette runs actions left without keys
+// in the config
+func TestPalette_UnboundAction(t *testing.T) {
+	task := &models.Task{Title: "Task", Priority: 3, Column: models.ColumnInbox}
+	m := newTestModel(t, task)
+	m.keys.Priority.SetKeys()
+	m.keys.Archive.SetKeys()
+
+	m, _ = press(t, m, ":")
+	m = typeText(t, m, "p2")
+	m, cmd := press(t, m, "enter")
+	if cmd == nil {
+		t.Fatal("Expected the priority to be set")
+	}
+	updated, _ := m.Update(cmd())
+	m = updated.(Model)
+	if got := m.findTask(task.ID).Priority; got != 2 {
+		t.Errorf("Expected priority 2, got %d", got)
+	}
+
+	m, _ = press(t, m, ":")
+	m = typeText(t, m, "archivetask")
+	m, cmd = press(t, m, "enter")
+	if cmd == nil {
+		t.Fatal("Expected the task to be archived")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if len(m.allTasks) != 0 {
+		t.Errorf("Expected the task archived, got %d active tasks", len(m.allTasks))
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query, text string
+		wantOK      bool
+	}{
+		{"mv", "Move task", true},
+		{"MOVE", "Move task", true},
+		{"mt", "Move task", true},
+		{"tm", "Move task", false},
+		{"movex", "Move task", false},
+		{"", "Move task", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if _, ok := fuzzyScore(tt.query, tt.text); ok != tt.wantOK {
+				t.Errorf("Expected %q matching %q to be %v", tt.query, tt.text, tt.wantOK)
+			}
+		})
+	}
+
+	// Consecutive characters and word starts score higher than scattered ones
+	better := []struct{ query, text, worse string }{
+		{"mov", "Move task", "Make it over"},
+		{"dt", "Delete task", "Add it"},
+		{"tt", "Task title", "Put it"},
+	}
+	for _, tt := range better {
+		high, _ := fuzzyScore(tt.query, tt.text)
+		low, ok := fuzzyScore(tt.query, tt.worse)
+		if !ok || high <= low {
+			t.Errorf("Expected %q to score higher on %q (%d) than on %q (%d)", tt.query, tt.text, high, tt.worse, low)
+		}
+	}
+}
+
+func TestMatchCommands(t *testing.T) {
+	commands := []paletteCommand{
+		{title: "Open task"},
+		{title: "Move task to..."},
+		{title: "Move to Done"},
+		{title: "Delete task"},
+	}
+	titles := func(commands []paletteCommand) []string {
+		var got []string
+		for _, command := range commands {
+			got = append(got, command.title)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty", query: "", want: []string{"Open task", "Move task to...", "Move to Done", "Delete task"}},
+		{name: "best first", query: "move done", want: []string{"Move to Done"}},
+		{name: "spaces ignored", query: "m o v e", want: []string{"Move task to...", "Move to Done"}},
+		{name: "consecutive first", query: "de", want: []string{"Delete task", "Move to Done"}},
+		{name: "scattered last", query: "ta", want: []string{"Open task", "Move task to...", "Delete task"}},
+		{name: "no match", query: "xyz", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := titles(matchCommands(commands, tt.query)); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// openQuickAdd opens the one-line quick-add prompt for the current column
+func (m Model) openQuickAdd() (Model, tea.Cmd) {
+	m.quickAddInput = textinput.New()
+	m.quickAddInput.Placeholder = "Fix login bug #bug !1 ^parent due:fri @in_progress"
+	m.quickAddInput.CharLimit = 500
+	m.quickAddInput.Width = 60
+	m.quickAddInput.Focus()
+	m.formErr = nil
+	m.viewMode = ViewModeQuickAdd
+	return m, textinput.Blink
+}
+
+// handleQuickAddKeys handles key presses in the quick-add prompt
+func (m Model) handleQuickAddKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, keys.Back) {
+		m.viewMode = ViewModeKanban
+		m.formErr = nil
+		return m, nil
+	}
+
+	if key.Matches(msg, keys.Select) {
+		return m.saveQuickAdd()
+	}
+
+	var cmd tea.Cmd
+	m.quickAddInput, cmd = m.quickAddInput.Update(msg)
+	return m, cmd
+}
+
+// saveQuickAdd creates the task typed in the quick-add prompt. Errors are
+// shown in the prompt so the input can be fixed.
+func (m Model) saveQuickAdd() (tea.Model, tea.Cmd) {
+	qa, err := service.ParseQuickAdd(m.quickAddInput.Value(), time.Now())
+	if err != nil {
+		m.formErr = err
+		return m, nil
+	}
+
+	task := &models.Task{
+		Title:    qa.Title,
+		BoardID:  m.boardID,
+		Priority: 3,
+		Column:   m.GetCurrentColumnName(),
+		Tags:     qa.Tags,
+		DueAt:    qa.DueAt,
+		ParentID: m.focusParentID(), // Subtask of the focused task, unless ^parent is given
+	}
+	if qa.Priority > 0 {
+		task.Priority = qa.Priority
+	}
+	if qa.Column != "" {
+		task.Column = qa.Column
+	}
+	if qa.ParentRef != "" {
+		parentID, err := m.resolveTaskRef(qa.ParentRef)
+		if err != nil {
+			m.formErr = err
+			return m, nil
+		}
+		task.ParentID = &parentID
+	}
+
+	if err := m.svc.Create(task); err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			m.formErr = err
+			return m, nil
+		}
+		m.err = err
+		return m, tea.Quit
+	}
+
+	// Make the new task the target of @last
+	saveLast := m.saveState("last task", func(s *config.State) { s.LastTaskID = task.ID })
+
+	// Focus the new task, following it if it went to another column
+	m.lastMovedTaskID = task.ID
+	m.viewMode = ViewModeKanban
+	m.formErr = nil
+	m.statusMessage = fmt.Sprintf("Created %s in %s", task.Title, formatColumnName(task.Column))
+	return m, tea.Batch(m.loadTasks, saveLast)
+}
+
+// renderQuickAdd renders the quick-add prompt
+func (m Model) renderQuickAdd() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGreen).
+		Render("Quick Add to " + formatColumnName(m.GetCurrentColumnName()))
+	b.WriteString(title + "\n\n")
+
+	var prompt strings.Builder
+	prompt.WriteString(m.quickAddInput.View())
+	prompt.WriteString("\n\n")
+	prompt.WriteString(formHelpStyle.Render("#tag  !1-!5 priority  ^parent  due:fri  @column  \\ keeps a word as-is"))
+	prompt.WriteString("\n")
+	prompt.WriteString(formHelpStyle.Render("enter: create • esc: cancel"))
+	if m.formErr != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(colorRed).
+			Bold(true)
+		prompt.WriteString("\n\n")
+		prompt.WriteString(errorStyle.Render("Error: " + m.formErr.Error()))
+	}
+
+	b.WriteString(quickAddStyle.Render(prompt.String()))
+	return b.String()
+}
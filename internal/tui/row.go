@@ -14,11 +14,7 @@ func (m Model) renderKanbanRows() string {
 	var b strings.Builder
 
 	// Title
-	title := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(gruvboxGreen).
-		Render("OnTop - Task Manager")
-	b.WriteString(title + "\n\n")
+	b.WriteString(m.renderTitle() + "\n\n")
 
 	// Get tasks by column
 	inboxTasks := m.GetTasksByColumn(models.ColumnInbox)
@@ -26,15 +22,15 @@ func (m Model) renderKanbanRows() string {
 	doneTasks := m.GetTasksByColumn(models.ColumnDone)
 
 	// Render each workflow stage as a horizontal row
-	inboxRow := m.renderRow("INBOX", inboxTasks, 0)
+	inboxRow := m.renderRow(columnTitles[0], inboxTasks, 0)
 	b.WriteString(inboxRow)
 	b.WriteString("\n")
 
-	inProgressRow := m.renderRow("IN PROGRESS", inProgressTasks, 1)
+	inProgressRow := m.renderRow(columnTitles[1], inProgressTasks, 1)
 	b.WriteString(inProgressRow)
 	b.WriteString("\n")
 
-	doneRow := m.renderRow("DONE", doneTasks, 2)
+	doneRow := m.renderRow(columnTitles[2], doneTasks, 2)
 	b.WriteString(doneRow)
 	b.WriteString("\n\n")
 
@@ -43,13 +39,25 @@ func (m Model) renderKanbanRows() string {
 	if m.showArchived {
 		viewMode = "Archived"
 	}
-	statusMsg := fmt.Sprintf("Total tasks: %d  •  Sort: %s  •  View: %s  •  Layout: Row", len(m.tasks), m.GetSortModeName(), viewMode)
+	statusMsg := fmt.Sprintf("DB: %s  •  Board: %s  •  Total tasks: %d  •  Sort: %s", m.dbPath, m.boardName, len(m.allTasks), m.GetSortModeName())
+	statusMsg += m.viewStatus()
+	statusMsg += fmt.Sprintf("  •  View: %s  •  Layout: Row", viewMode)
+	statusMsg += m.filterStatus()
+	statusMsg += m.swimlaneStatus()
+	statusMsg += m.markedStatus()
+	if m.statusMessage != "" {
+		statusMsg += "  •  " + m.statusMessage
+	}
 	b.WriteString(statusBarStyle.Render(statusMsg))
 	b.WriteString("\n")
 
-	// Help view
-	helpView := m.help.View(m.keys)
-	b.WriteString(helpStyle.Render(helpView))
+	// Help view, or the filter bar while typing a filter
+	if m.viewMode == ViewModeFilter {
+		b.WriteString(m.renderFilterBar())
+	} else {
+		helpView := m.help.View(m.keys)
+		b.WriteString(helpStyle.Render(helpView))
+	}
 
 	return b.String()
 }
@@ -78,53 +86,20 @@ func (m Model) renderRow(title string, tasks []*models.Task, rowIndex int) strin
 	b.WriteString("\n\n")
 
 	// Render tasks vertically (one per line)
-	if len(tasks) == 0 {
-		// Empty row placeholder
-		b.WriteString(taskStyle.Render("(no tasks)"))
-	} else {
-		// Calculate max tasks to show based on available height
-		// Each row gets roughly equal space
-		maxTasks := (m.height - 15) / 3 // Divide remaining height by 3 rows
-		if maxTasks < 3 {
-			maxTasks = 3
-		}
-
-		// Card width for task rendering
-		cardWidth := m.width - 10 // Leave margin for borders
-
-		// Render tasks vertically
-		for i, task := range tasks {
-			if i >= maxTasks {
-				remaining := len(tasks) - maxTasks
-				b.WriteString(taskStyle.Render(fmt.Sprintf("... +%d more", remaining)))
-				break
-			}
-
-			isSelected := isSelectedRow && i == m.selectedTask
-			isSubtask := task.ParentID != nil
-
-			cardContent := m.renderTaskCard(task, cardWidth-4, isSubtask)
-
-			if isSelected {
-				b.WriteString(selectedTaskStyle.Render(cardContent))
-			} else {
-				b.WriteString(taskStyle.Render(cardContent))
-			}
-			b.WriteString("\n")
-		}
-	}
+	cardWidth := m.width - 10 // Leave margin for borders
+	b.WriteString(m.renderTaskList(tasks, rowIndex, cardWidth-4))
 
 	// Row container style with border
 	content := b.String()
 	rowStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(gruvboxGray).
+		BorderForeground(colorGray).
 		Padding(0, 1).
 		Width(m.width - 4)
 
 	// Selected row has different border color
 	if isSelectedRow {
-		rowStyle = rowStyle.BorderForeground(gruvboxGreen).Bold(true)
+		rowStyle = rowStyle.BorderForeground(colorGreen).Bold(true)
 	}
 
 	return rowStyle.Render(content)
@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// markMarker is shown before marked task cards
+const markMarker = "● "
+
+// handleMarkKeys handles the multi-select keys in kanban view. Returns false
+// if the key is not a mark key.
+func (m Model) handleMarkKeys(msg tea.KeyMsg, keys KeyMap) (Model, bool) {
+	switch {
+	case key.Matches(msg, keys.Mark):
+		m.toggleMark()
+		return m, true
+
+	case key.Matches(msg, keys.MarkRange):
+		m.markRange()
+		return m, true
+
+	case key.Matches(msg, keys.MarkAll):
+		m.toggleMarkColumn()
+		return m, true
+
+	case key.Matches(msg, keys.Back) && len(m.marked) > 0:
+		m.clearMarks()
+		return m, true
+	}
+
+	return m, false
+}
+
+// toggleMark marks the selected task, or unmarks it if it is marked. The
+// task becomes the anchor of the next range.
+func (m *Model) toggleMark() {
+	task := m.GetSelectedTask()
+	if task == nil {
+		return
+	}
+	if m.marked[task.ID] {
+		delete(m.marked, task.ID)
+	} else {
+		m.marked[task.ID] = true
+	}
+	m.markAnchor = task.ID
+}
+
+// toggleMarkColumn marks the whole current column, or unmarks it if it is
+// already fully marked
+func (m *Model) toggleMarkColumn() {
+	columnTasks := m.GetTasksByColumn(m.GetCurrentColumnName())
+	allMarked := len(columnTasks) > 0
+	for _, task := range columnTasks {
+		if !m.marked[task.ID] {
+			allMarked = false
+			break
+		}
+	}
+	for _, task := range columnTasks {
+		if allMarked {
+			delete(m.marked, task.ID)
+		} else {
+			m.marked[task.ID] = true
+		}
+	}
+}
+
+// markRange marks every task between the anchor (the last task marked with
+// space) and the selected task. Without an anchor in the current column,
+// only the selected task is marked and becomes the anchor.
+func (m *Model) markRange() {
+	columnTasks := m.GetTasksByColumn(m.GetCurrentColumnName())
+	if m.selectedTask < 0 || m.selectedTask >= len(columnTasks) {
+		return
+	}
+
+	anchor := -1
+	for i, task := range columnTasks {
+		if task.ID == m.markAnchor {
+			anchor = i
+			break
+		}
+	}
+	if anchor < 0 {
+		anchor = m.selectedTask
+		m.markAnchor = columnTasks[anchor].ID
+	}
+
+	from, to := anchor, m.selectedTask
+	if from > to {
+		from, to = to, from
+	}
+	for _, task := range columnTasks[from : to+1] {
+		m.marked[task.ID] = true
+	}
+}
+
+// clearMarks unmarks every task
+func (m *Model) clearMarks() {
+	m.marked = make(map[string]bool)
+	m.markAnchor = ""
+}
+
+// pruneMarks unmarks tasks that are no longer on the board
+func (m *Model) pruneMarks() {
+	present := make(map[string]bool, len(m.tasks))
+	for _, task := range m.tasks {
+		present[task.ID] = true
+	}
+	for id := range m.marked {
+		if !present[id] {
+			delete(m.marked, id)
+		}
+	}
+}
+
+// markedIDs returns the marked task IDs in board order
+func (m *Model) markedIDs() []string {
+	var ids []string
+	for _, column := range models.ValidColumns() {
+		for _, task := range m.GetTasksByColumn(column) {
+			if m.marked[task.ID] {
+				ids = append(ids, task.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// targetIDs returns the tasks a kanban action applies to: the marked tasks,
+// or the selected task if nothing is marked
+func (m *Model) targetIDs() []string {
+	if len(m.marked) > 0 {
+		return m.markedIDs()
+	}
+	if task := m.GetSelectedTask(); task != nil {
+		return []string{task.ID}
+	}
+	return nil
+}
+
+// findTask returns the loaded task with the given ID, or nil
+func (m *Model) findTask(id string) *models.Task {
+	for _, task := range m.tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// applyBulk applies patch to every task in ids in one transaction, then
+// clears the marks and reloads
+func (m Model) applyBulk(ids []string, patch service.TaskPatch, status string) (Model, tea.Cmd) {
+	if len(ids) == 0 {
+		return m, nil
+	}
+
+	if _, err := m.svc.UpdateMany(ids, patch); err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) || errors.Is(err, service.ErrTaskNotFound) {
+			m.statusMessage = fmt.Sprintf("Nothing changed: %v", err)
+			return m, m.loadTasks
+		}
+		m.err = err
+		return m, tea.Quit
+	}
+
+	m.clearMarks()
+	m.statusMessage = fmt.Sprintf(status, len(ids))
+	return m, m.loadTasks
+}
+
+// confirmBulkMove moves every task in bulkIDs to the selected column
+func (m Model) confirmBulkMove() (tea.Model, tea.Cmd) {
+	columns := []string{models.ColumnInbox, models.ColumnInProgress, models.ColumnDone}
+	targetColumn := columns[m.moveSelection]
+
+	// Skip tasks already in the target column
+	var ids []string
+	for _, id := range m.bulkIDs {
+		if task := m.findTask(id); task == nil || task.Column != targetColumn {
+			ids = append(ids, id)
+		}
+	}
+
+	m.viewMode = ViewModeKanban
+	m.bulkIDs = nil
+	m.moveTask = nil
+	m.currentColumn = m.moveSelection
+	m.selectedTask = 0
+	if len(ids) == 0 {
+		m.clearMarks()
+		return m, nil
+	}
+	return m.applyBulk(ids, service.TaskPatch{Column: &targetColumn}, "Moved %d task(s) to "+formatColumnName(targetColumn))
+}
+
+// confirmBulkDelete deletes every task in bulkIDs
+func (m Model) confirmBulkDelete() (tea.Model, tea.Cmd) {
+	ids := m.bulkIDs
+	m.viewMode = ViewModeKanban
+	m.bulkIDs = nil
+
+	deleted, err := m.svc.DeleteMany(ids)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			m.statusMessage = fmt.Sprintf("Nothing deleted: %v", err)
+			return m, m.loadTasks
+		}
+		m.err = err
+		return m, tea.Quit
+	}
+
+	m.clearMarks()
+	m.statusMessage = fmt.Sprintf("Deleted %d task(s)", deleted)
+	return m, m.loadTasks
+}
+
+// openTagPrompt opens the tag prompt for the target tasks
+func (m Model) openTagPrompt() (Model, tea.Cmd) {
+	ids := m.targetIDs()
+	if len(ids) == 0 {
+		return m, nil
+	}
+
+	m.bulkIDs = ids
+	m.tagInput = textinput.New()
+	m.tagInput.Placeholder = "urgent, -old  (prefix with - to remove)"
+	m.tagInput.CharLimit = 200
+	m.tagInput.Width = 50
+	m.tagInput.Focus()
+	m.viewMode = ViewModeTagPrompt
+	return m, textinput.Blink
+}
+
+// handleTagPromptKeys handles key presses in the tag prompt
+func (m Model) handleTagPromptKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, keys.Back) {
+		m.viewMode = ViewModeKanban
+		m.bulkIDs = nil
+		return m, nil
+	}
+
+	if key.Matches(msg, keys.Select) {
+		add, remove := parseTagEdit(m.tagInput.Value())
+		ids := m.bulkIDs
+		m.viewMode = ViewModeKanban
+		m.bulkIDs = nil
+		if len(add) == 0 && len(remove) == 0 {
+			return m, nil
+		}
+		return m.applyBulk(ids, service.TaskPatch{AddTags: add, RemoveTags: remove}, "Updated tags on %d task(s)")
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// parseTagEdit splits a comma or space separated tag list into tags to add
+// and tags to remove (prefixed with -)
+func parseTagEdit(input string) (add, remove []string) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for _, field := range fields {
+		switch {
+		case strings.HasPrefix(field, "-"):
+			if tag := strings.TrimPrefix(field, "-"); tag != "" {
+				remove = append(remove, tag)
+			}
+		default:
+			if tag := strings.TrimPrefix(field, "+"); tag != "" {
+				add = append(add, tag)
+			}
+		}
+	}
+	return add, remove
+}
+
+// renderTagPrompt renders the tag prompt
+func (m Model) renderTagPrompt() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorAqua).
+		Render("Edit Tags")
+	b.WriteString(title + "\n\n")
+
+	b.WriteString(m.renderBulkTargets() + "\n")
+
+	var prompt strings.Builder
+	prompt.WriteString("Tags to add, or to remove with a - prefix:\n\n")
+	prompt.WriteString(m.tagInput.View())
+	prompt.WriteString("\n\n")
+	prompt.WriteString(formHelpStyle.Render("enter: apply • esc: cancel"))
+
+	b.WriteString(tagPromptStyle.Render(prompt.String()))
+	return b.String()
+}
+
+// renderBulkTargets describes the tasks in bulkIDs, listing a few titles
+func (m Model) renderBulkTargets() string {
+	const maxListed = 5
+
+	infoStyle := lipgloss.NewStyle().Foreground(colorGray)
+	if len(m.bulkIDs) == 1 {
+		if task := m.findTask(m.bulkIDs[0]); task != nil {
+			return infoStyle.Render(fmt.Sprintf("Task: %s", task.Title))
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(infoStyle.Render(fmt.Sprintf("%d tasks:", len(m.bulkIDs))) + "\n")
+	for i, id := range m.bulkIDs {
+		if i == maxListed {
+			b.WriteString(infoStyle.Render(fmt.Sprintf("  ... and %d more", len(m.bulkIDs)-maxListed)) + "\n")
+			break
+		}
+		if task := m.findTask(id); task != nil {
+			b.WriteString(infoStyle.Render("  "+markMarker+task.Title) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// cardStyle picks the card style for a task on the board
+func (m Model) cardStyle(task *models.Task, isSelected bool) lipgloss.Style {
+	switch {
+	case isSelected && m.marked[task.ID]:
+		return selectedMarkedTaskStyle
+	case isSelected:
+		return selectedTaskStyle
+	case m.marked[task.ID]:
+		return markedTaskStyle
+	}
+	return taskStyle
+}
+
+// markedStatus returns the status bar segment for marked tasks
+func (m Model) markedStatus() string {
+	if len(m.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  •  Marked: %d (esc to clear)", len(m.marked))
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// inboxTasks returns n inbox tasks, sorted in the given order by priority
+func inboxTasks(n int) []*models.Task {
+	tasks := make([]*models.Task, n)
+	for i := range tasks {
+		tasks[i] = &models.Task{Title: string(rune('A' + i)), Priority: min(i+1, 5), Column: models.ColumnInbox}
+	}
+	return tasks
+}
+
+// markedTitles returns the titles of the marked tasks in board order
+func markedTitles(m Model) []string {
+	var titles []string
+	for _, id := range m.markedIDs() {
+		titles = append(titles, m.findTask(id).Title)
+	}
+	return titles
+}
+
+func TestMarkKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want []string
+	}{
+		{name: "mark", keys: []string{" ", "j", "j", " "}, want: []string{"A", "C"}},
+		{name: "unmark", keys: []string{" ", " "}},
+		{name: "range down", keys: []string{"j", " ", "j", "j", "V"}, want: []string{"B", "C", "D"}},
+		{name: "range up", keys: []string{"j", "j", "j", " ", "k", "k", "V"}, want: []string{"B", "C", "D"}},
+		{name: "range without anchor", keys: []string{"j", "V"}, want: []string{"B"}},
+		{name: "range from the last mark", keys: []string{" ", "j", "j", " ", "j", "V"}, want: []string{"A", "C", "D"}},
+		{name: "column", keys: []string{" ", "*"}, want: []string{"A", "B", "C", "D"}},
+		{name: "column again unmarks", keys: []string{"*", "*"}},
+		{name: "esc clears", keys: []string{" ", "j", " ", "esc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, inboxTasks(4)...)
+			for _, k := range tt.keys {
+				m, _ = press(t, m, k)
+			}
+			if got := markedTitles(m); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v marked, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTargetIDs(t *testing.T) {
+	tasks := append(inboxTasks(2), &models.Task{Title: "Done", Priority: 1, Column: models.ColumnDone})
+	m := newTestModel(t, tasks...)
+
+	if got := m.targetIDs(); !slices.Equal(got, []string{tasks[0].ID}) {
+		t.Errorf("Expected the selected task without marks, got %v", got)
+	}
+
+	// Marked tasks come in board order, whatever the order they were marked in
+	m.marked[tasks[2].ID] = true
+	m.marked[tasks[1].ID] = true
+	if got, want := m.targetIDs(), []string{tasks[1].ID, tasks[2].ID}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestPruneMarks(t *testing.T) {
+	tasks := inboxTasks(3)
+	m := newTestModel(t, tasks...)
+	m, _ = press(t, m, "*")
+
+	if err := m.svc.Delete(tasks[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := m.Update(m.loadTasks())
+	m = updated.(Model)
+	if got, want := markedTitles(m), []string{"A", "C"}; !slices.Equal(got, want) || len(m.marked) != 2 {
+		t.Errorf("Expected %v marked after the reload, got %v", want, got)
+	}
+}
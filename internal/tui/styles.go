@@ -0,0 +1,188 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colors of the current theme, set by applyTheme
+var (
+	colorBg        lipgloss.TerminalColor
+	colorSelection lipgloss.TerminalColor
+	colorFg        lipgloss.TerminalColor
+	colorGray      lipgloss.TerminalColor
+	colorRed       lipgloss.TerminalColor
+	colorOrange    lipgloss.TerminalColor
+	colorYellow    lipgloss.TerminalColor
+	colorGreen     lipgloss.TerminalColor
+	colorAqua      lipgloss.TerminalColor
+
+	priorityColors map[int]lipgloss.TerminalColor
+)
+
+// Shared styles, built from the current theme by applyTheme
+var (
+	// Column styles (width will be set dynamically)
+	columnStyle       lipgloss.Style
+	activeColumnStyle lipgloss.Style
+
+	// Column header styles
+	inboxHeaderStyle      lipgloss.Style
+	inProgressHeaderStyle lipgloss.Style
+	doneHeaderStyle       lipgloss.Style
+	laneHeaderStyle       lipgloss.Style // Swimlane headers, colored by lane
+
+	// Task card styles
+	taskStyle               lipgloss.Style
+	selectedTaskStyle       lipgloss.Style
+	markedTaskStyle         lipgloss.Style
+	selectedMarkedTaskStyle lipgloss.Style
+
+	// Status bar style
+	statusBarStyle lipgloss.Style
+	helpStyle      lipgloss.Style
+
+	// Detail view styles
+	detailLabelStyle   lipgloss.Style
+	detailValueStyle   lipgloss.Style
+	detailSectionStyle lipgloss.Style
+
+	// Form styles
+	formStyle      lipgloss.Style
+	formLabelStyle lipgloss.Style
+	formHelpStyle  lipgloss.Style
+
+	// Dialog styles
+	movePromptStyle            lipgloss.Style
+	moveOptionStyle            lipgloss.Style
+	moveSelectedStyle          lipgloss.Style
+	confirmPromptStyle         lipgloss.Style
+	confirmButtonStyle         lipgloss.Style
+	confirmButtonSelectedStyle lipgloss.Style
+	tagPromptStyle             lipgloss.Style
+	quickAddStyle              lipgloss.Style
+	templatePickerStyle        lipgloss.Style
+	boardPickerStyle           lipgloss.Style
+	paletteStyle               lipgloss.Style
+)
+
+func init() {
+	applyTheme(builtinThemes[defaultDarkTheme])
+}
+
+// applyTheme makes t the current theme, rebuilding the shared styles. Themes
+// without colors, like monochrome, mark the selection with reverse video.
+func applyTheme(t Theme) {
+	colorBg = t.Background
+	colorSelection = t.Selection
+	colorFg = t.Text
+	colorGray = t.Gray
+	colorRed = t.Red
+	colorOrange = t.Orange
+	colorYellow = t.Yellow
+	colorGreen = t.Green
+	colorAqua = t.Aqua
+	priorityColors = map[int]lipgloss.TerminalColor{
+		1: colorRed,
+		2: colorOrange,
+		3: colorYellow,
+		4: colorGreen,
+		5: colorGray,
+	}
+
+	// highlight fills a style with bg, or reverses it if there are no colors
+	highlight := func(s lipgloss.Style, bg, fg lipgloss.TerminalColor) lipgloss.Style {
+		if bg == (lipgloss.NoColor{}) {
+			return s.Reverse(true)
+		}
+		return s.Background(bg).Foreground(fg)
+	}
+	dialog := func(border lipgloss.TerminalColor) lipgloss.Style {
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(border).
+			Padding(1, 2).
+			MarginTop(2).
+			MarginBottom(2)
+	}
+
+	columnStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorGray).
+		Padding(0, 1)
+	activeColumnStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorGreen).
+		Padding(0, 1)
+	if colorGreen == (lipgloss.NoColor{}) {
+		activeColumnStyle = activeColumnStyle.Border(lipgloss.ThickBorder())
+	}
+
+	inboxHeaderStyle = lipgloss.NewStyle().
+		Foreground(colorAqua).
+		Bold(true)
+	inProgressHeaderStyle = lipgloss.NewStyle().
+		Foreground(colorYellow).
+		Bold(true)
+	doneHeaderStyle = lipgloss.NewStyle().
+		Foreground(colorGreen).
+		Bold(true)
+	laneHeaderStyle = lipgloss.NewStyle().
+		Underline(true).
+		Bold(true)
+
+	taskStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		MarginBottom(0)
+	selectedTaskStyle = highlight(taskStyle, colorSelection, colorFg)
+	markedTaskStyle = taskStyle.
+		Foreground(colorAqua)
+	selectedMarkedTaskStyle = highlight(taskStyle, colorSelection, colorAqua).
+		Bold(true)
+
+	statusBarStyle = lipgloss.NewStyle().
+		Foreground(colorGray).
+		MarginTop(1)
+	helpStyle = lipgloss.NewStyle().
+		Foreground(colorGray)
+
+	detailLabelStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGray)
+	detailValueStyle = lipgloss.NewStyle().
+		Foreground(colorFg)
+	detailSectionStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorGray).
+		Padding(1, 2).
+		MarginBottom(1)
+
+	formStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorGreen).
+		Padding(1, 2).
+		MarginTop(1)
+	formLabelStyle = lipgloss.NewStyle().
+		Foreground(colorGray).
+		Bold(true)
+	formHelpStyle = lipgloss.NewStyle().
+		Foreground(colorGray).
+		Italic(true)
+
+	movePromptStyle = dialog(colorGreen)
+	moveOptionStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		MarginRight(2)
+	moveSelectedStyle = highlight(moveOptionStyle, colorGreen, colorBg).
+		Bold(true)
+
+	confirmPromptStyle = dialog(colorRed)
+	confirmButtonStyle = lipgloss.NewStyle().
+		Padding(0, 2).
+		MarginRight(2)
+	confirmButtonSelectedStyle = highlight(confirmButtonStyle, colorRed, colorBg).
+		Bold(true)
+
+	tagPromptStyle = dialog(colorAqua)
+	quickAddStyle = dialog(colorGreen)
+	templatePickerStyle = dialog(colorGreen)
+	boardPickerStyle = dialog(colorGreen)
+	paletteStyle = dialog(colorGreen)
+}
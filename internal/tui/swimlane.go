@@ -0,0 +1,268 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// SwimlaneMode is the dimension the board is split into swimlanes by, across
+// the workflow columns
+type SwimlaneMode int
+
+// Swimlane modes, in the order of config.SwimlaneModes
+const (
+	SwimlaneNone SwimlaneMode = iota
+	SwimlanePriority
+	SwimlaneTag
+	SwimlaneParent
+)
+
+// lane is one swimlane of the board
+type lane struct {
+	key   string                    // Priority, tag or parent ID; "" for tasks without one
+	order int                       // Position of the lane, ties broken by key
+	title string                    // Header text, without the count
+	color lipgloss.TerminalColor    // Header color
+	tasks map[string][]*models.Task // Tasks by column, in hierarchical display order
+}
+
+// boardLine is a line of a column's (or row's) task list: a task card, a
+// lane header or padding that keeps the lanes aligned across columns
+type boardLine struct {
+	task int   // Index of the task in the column, or -1
+	lane *lane // Lane whose header is on this line, or nil
+}
+
+// GetSwimlaneModeName returns the display name for the current swimlane mode
+func (m *Model) GetSwimlaneModeName() string {
+	switch m.swimlanes {
+	case SwimlanePriority:
+		return "Priority"
+	case SwimlaneTag:
+		return "Tag"
+	case SwimlaneParent:
+		return "Parent"
+	default:
+		return "None"
+	}
+}
+
+// ToggleSwimlanes cycles to the next swimlane mode, keeping the selection
+// on the same task
+func (m *Model) ToggleSwimlanes() {
+	var selectedID string
+	if task := m.GetSelectedTask(); task != nil {
+		selectedID = task.ID
+	}
+	m.swimlanes = (m.swimlanes + 1) % SwimlaneMode(len(config.SwimlaneModes))
+	clear(m.columnScrollOffset)
+	clear(m.rowScrollOffset)
+	m.restoreSelection(selectedID)
+}
+
+// lanes splits the shown tasks into the swimlanes of the current mode, in
+// display order. Lanes without tasks are left out. With tag swimlanes, a
+// task with several tags is shown in the lane of each one.
+func (m *Model) lanes() []*lane {
+	var visible []*models.Task
+	hasSubtasks := make(map[string]bool)
+	for _, task := range m.tasks {
+		if m.inFocus(task) {
+			visible = append(visible, task)
+			if task.ParentID != nil {
+				hasSubtasks[*task.ParentID] = true
+			}
+		}
+	}
+
+	// Parent lanes follow the order the parents are sorted in
+	parentOrder := make(map[string]int)
+	if m.swimlanes == SwimlaneParent {
+		var parents []*models.Task
+		for _, task := range visible {
+			if hasSubtasks[task.ID] {
+				parents = append(parents, task)
+			}
+		}
+		for i, ht := range service.BuildFlatHierarchy(parents, m.sortMode) {
+			parentOrder[ht.Task.ID] = i
+		}
+	}
+
+	byKey := make(map[string]*lane)
+	var lanes []*lane
+	for _, task := range visible {
+		for _, key := range m.laneKeys(task, hasSubtasks, parentOrder) {
+			l := byKey[key]
+			if l == nil {
+				l = m.newLane(key, parentOrder)
+				byKey[key] = l
+				lanes = append(lanes, l)
+			}
+			l.tasks[task.Column] = append(l.tasks[task.Column], task)
+		}
+	}
+
+	slices.SortFunc(lanes, func(a, b *lane) int {
+		return cmp.Or(cmp.Compare(a.order, b.order), cmp.Compare(a.key, b.key))
+	})
+	for _, l := range lanes {
+		for column, tasks := range l.tasks {
+			l.tasks[column] = m.hierarchy(tasks)
+		}
+	}
+	return lanes
+}
+
+// laneKeys returns the keys of the lanes task is shown in
+func (m *Model) laneKeys(task *models.Task, hasSubtasks map[string]bool, parentOrder map[string]int) []string {
+	switch m.swimlanes {
+	case SwimlanePriority:
+		return []string{strconv.Itoa(task.Priority)}
+	case SwimlaneTag:
+		if len(task.Tags) == 0 {
+			return []string{""}
+		}
+		return task.Tags
+	case SwimlaneParent:
+		// Subtasks whose parent isn't shown have no lane of their own
+		if task.ParentID != nil {
+			if _, ok := parentOrder[*task.ParentID]; ok {
+				return []string{*task.ParentID}
+			}
+		}
+		if hasSubtasks[task.ID] {
+			return []string{task.ID}
+		}
+	}
+	return []string{""}
+}
+
+// newLane returns an empty lane for key in the current swimlane mode. Lanes
+// for tasks without a tag or parent come last.
+func (m *Model) newLane(key string, parentOrder map[string]int) *lane {
+	l := &lane{key: key, title: key, color: colorFg, tasks: make(map[string][]*models.Task)}
+	switch m.swimlanes {
+	case SwimlanePriority:
+		priority, _ := strconv.Atoi(key)
+		l.order = priority
+		l.title = fmt.Sprintf("P%d", priority)
+		if color, ok := priorityColors[priority]; ok {
+			l.color = color
+		}
+	case SwimlaneTag:
+		l.color = colorAqua
+		if key == "" {
+			l.order, l.title, l.color = 1, "No tags", colorGray
+		}
+	case SwimlaneParent:
+		if parent := m.findTask(key); parent != nil {
+			l.order = parentOrder[key]
+			l.title = parent.Title
+		}
+		if key == "" {
+			l.order, l.title, l.color = len(parentOrder), "No parent", colorGray
+		}
+	}
+	return l
+}
+
+// columnLines returns the lines of a column's (or row's) task list. Without
+// swimlanes there is a line per task. With them, each lane starts with a
+// header; in column layout, lanes are padded to the same height in every
+// column so that they line up.
+func (m *Model) columnLines(column int) []boardLine {
+	name := models.ValidColumns()[column]
+	if m.swimlanes == SwimlaneNone {
+		lines := make([]boardLine, len(m.GetTasksByColumn(name)))
+		for i := range lines {
+			lines[i] = boardLine{task: i}
+		}
+		return lines
+	}
+
+	var lines []boardLine
+	task := 0
+	for _, l := range m.lanes() {
+		count := len(l.tasks[name])
+		height := count
+		if m.viewLayout == LayoutColumn {
+			for _, tasks := range l.tasks {
+				height = max(height, len(tasks))
+			}
+		}
+		if height == 0 {
+			continue
+		}
+		lines = append(lines, boardLine{task: -1, lane: l})
+		for i := range height {
+			if i < count {
+				lines = append(lines, boardLine{task: task})
+				task++
+			} else {
+				lines = append(lines, boardLine{task: -1})
+			}
+		}
+	}
+	return lines
+}
+
+// taskLine returns the index of the line showing the task at index task of
+// a column, or -1 if there is none
+func taskLine(lines []boardLine, task int) int {
+	return slices.IndexFunc(lines, func(line boardLine) bool { return line.task == task })
+}
+
+// jumpLane selects the first task of the next (delta 1) or previous (delta
+// -1) lane that has tasks in the current column
+func (m *Model) jumpLane(delta int) {
+	if m.swimlanes == SwimlaneNone {
+		return
+	}
+	name := m.GetCurrentColumnName()
+	var starts []int // Index of the first task of each lane in the column
+	task := 0
+	for _, l := range m.lanes() {
+		if count := len(l.tasks[name]); count > 0 {
+			starts = append(starts, task)
+			task += count
+		}
+	}
+
+	// The lane holding the selection
+	current := 0
+	for i, start := range starts {
+		if start <= m.selectedTask {
+			current = i
+		}
+	}
+	if next := current + delta; next >= 0 && next < len(starts) {
+		m.selectedTask = starts[next]
+	}
+}
+
+// renderLaneHeader renders a lane's header in a column, with the lane's
+// number of tasks in the column
+func renderLaneHeader(l *lane, count, width int) string {
+	title := l.title
+	if runes := []rune(title); len(runes) > width-6 && width > 9 {
+		title = string(runes[:width-9]) + "..."
+	}
+	return laneHeaderStyle.Foreground(l.color).Render(fmt.Sprintf("%s (%d)", title, count))
+}
+
+// swimlaneStatus returns the status bar note for the swimlane mode, or "" if
+// there are no swimlanes
+func (m Model) swimlaneStatus() string {
+	if m.swimlanes == SwimlaneNone {
+		return ""
+	}
+	return "  •  Lanes: " + m.GetSwimlaneModeName()
+}
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lucasefe/ontop/internal/models"
+)
+
+// laneSummary returns the title of each lane with the titles of its tasks in
+// a column
+func laneSummary(m Model, column string) map[string][]string {
+	summary := make(map[string][]string)
+	for _, l := range m.lanes() {
+		titles := []string{}
+		for _, task := range l.tasks[column] {
+			titles = append(titles, task.Title)
+		}
+		summary[l.title] = titles
+	}
+	return summary
+}
+
+// laneTitles returns the titles of the lanes in display order
+func laneTitles(m Model) []string {
+	var titles []string
+	for _, l := range m.lanes() {
+		titles = append(titles, l.title)
+	}
+	return titles
+}
+
+func TestLanes(t *testing.T) {
+	parent := &models.Task{Title: "Parent", Priority: 2, Column: models.ColumnInbox}
+	tasks := []*models.Task{
+		parent,
+		{Title: "Sub", Priority: 1, Column: models.ColumnInProgress, ParentID: &parent.ID},
+		{Title: "Bug", Priority: 1, Column: models.ColumnInbox, Tags: []string{"bug", "ui"}},
+		{Title: "Docs", Priority: 3, Column: models.ColumnDone, Tags: []string{"docs"}},
+		{Title: "Plain", Priority: 3, Column: models.ColumnInbox},
+	}
+
+	tests := []struct {
+		name   string
+		mode   SwimlaneMode
+		titles []string            // Lane titles, in order
+		inbox  map[string][]string // Inbox tasks by lane title
+	}{
+		{
+			name:   "priority",
+			mode:   SwimlanePriority,
+			titles: []string{"P1", "P2", "P3"},
+			inbox:  map[string][]string{"P1": {"Bug"}, "P2": {"Parent"}, "P3": {"Plain"}},
+		},
+		{
+			name:   "tag",
+			mode:   SwimlaneTag,
+			titles: []string{"bug", "docs", "ui", "No tags"},
+			inbox:  map[string][]string{"bug": {"Bug"}, "docs": {}, "ui": {"Bug"}, "No tags": {"Parent", "Plain"}},
+		},
+		{
+			name:   "parent",
+			mode:   SwimlaneParent,
+			titles: []string{"Parent", "No parent"},
+			inbox:  map[string][]string{"Parent": {"Parent"}, "No parent": {"Bug", "Plain"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, tasks...)
+			m.swimlanes = tt.mode
+
+			if got := laneTitles(m); !slices.Equal(got, tt.titles) {
+				t.Errorf("Expected lanes %v, got %v", tt.titles, got)
+			}
+			got := laneSummary(m, models.ColumnInbox)
+			for title, want := range tt.inbox {
+				if !slices.Equal(got[title], want) {
+					t.Errorf("Expected %v in the inbox of lane %s, got %v", want, title, got[title])
+				}
+			}
+		})
+	}
+}
+
+// TestLanes_Parent tests subtasks follow their parent's lane into other
+// columns, and that subtasks whose parent isn't shown have no lane of their
+// own
+func TestLanes_Parent(t *testing.T) {
+	parent := &models.Task{Title: "Parent", Priority: 2, Column: models.ColumnInbox}
+	tasks := []*models.Task{
+		parent,
+		{Title: "Sub", Priority: 1, Column: models.ColumnInProgress, ParentID: &parent.ID},
+	}
+	m := newTestModel(t, tasks...)
+	m.swimlanes = SwimlaneParent
+
+	if got := laneSummary(m, models.ColumnInProgress)["Parent"]; !slices.Equal(got, []string{"Sub"}) {
+		t.Errorf("Expected the subtask in its parent's lane, got %v", got)
+	}
+
+	// Archiving the parent hides it
+	if _, err := m.svc.Archive(parent.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := m.Update(m.loadTasks())
+	m = updated.(Model)
+	if got, want := laneTitles(m), []string{"No parent"}; !slices.Equal(got, want) {
+		t.Errorf("Expected lanes %v, got %v", want, got)
+	}
+}
+
+// TestColumnLines tests lanes get a header and are padded to line up across
+// columns in column layout, but not in row layout
+func TestColumnLines(t *testing.T) {
+	tasks := []*models.Task{
+		{Title: "A", Priority: 1, Column: models.ColumnInbox},
+		{Title: "B", Priority: 1, Column: models.ColumnInbox},
+		{Title: "C", Priority: 1, Column: models.ColumnDone},
+		{Title: "D", Priority: 2, Column: models.ColumnDone},
+	}
+	m := newTestModel(t, tasks...)
+	m.swimlanes = SwimlanePriority
+
+	// describe renders lines as "H" for a header, "-" for padding and the
+	// task's index otherwise
+	describe := func(lines []boardLine) []string {
+		var got []string
+		for _, line := range lines {
+			switch {
+			case line.lane != nil:
+				got = append(got, "H")
+			case line.task < 0:
+				got = append(got, "-")
+			default:
+				got = append(got, string(rune('0'+line.task)))
+			}
+		}
+		return got
+	}
+
+	tests := []struct {
+		name   string
+		layout ViewLayout
+		column int
+		want   []string
+	}{
+		{name: "inbox columns", layout: LayoutColumn, column: 0, want: []string{"H", "0", "1", "H", "-"}},
+		{name: "done columns", layout: LayoutColumn, column: 2, want: []string{"H", "0", "-", "H", "1"}},
+		{name: "in progress columns", layout: LayoutColumn, column: 1, want: []string{"H", "-", "-", "H", "-"}},
+		{name: "inbox rows", layout: LayoutRow, column: 0, want: []string{"H", "0", "1"}},
+		{name: "done rows", layout: LayoutRow, column: 2, want: []string{"H", "0", "H", "1"}},
+		{name: "in progress rows", layout: LayoutRow, column: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.setLayout(tt.layout)
+			if got := describe(m.columnLines(tt.column)); !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/service"
+	"github.com/lucasefe/ontop/internal/storage"
+)
+
+// openTemplatePicker opens the template picker from the create form. The
+// form stays as it was, so esc returns to it.
+func (m Model) openTemplatePicker() (tea.Model, tea.Cmd) {
+	names, err := service.ListTemplates(config.GetTemplatesDir())
+	if err != nil {
+		m.formErr = err
+		return m, nil
+	}
+	if len(names) == 0 {
+		m.formErr = fmt.Errorf("no templates in %s (see 'ontop template')", config.GetTemplatesDir())
+		return m, nil
+	}
+
+	m.templateNames = names
+	m.templateSelection = 0
+	m.template = nil
+	m.templateInputs = nil
+	m.formErr = nil
+	m.viewMode = ViewModeTemplate
+	return m, nil
+}
+
+// handleTemplateKeys handles key presses in the template picker: first
+// choosing a template, then filling in its variables
+func (m Model) handleTemplateKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	if m.template == nil {
+		return m.handleTemplateListKeys(msg, keys)
+	}
+
+	switch {
+	case key.Matches(msg, keys.Back):
+		// Back to the list
+		m.template = nil
+		m.templateInputs = nil
+		m.formErr = nil
+		return m, nil
+
+	case key.Matches(msg, keys.Save):
+		return m.createFromTemplate()
+
+	case key.Matches(msg, keys.Select):
+		if m.templateFocus == len(m.templateInputs)-1 {
+			return m.createFromTemplate()
+		}
+		return m.focusTemplateInput(m.templateFocus + 1), nil
+
+	case key.Matches(msg, keys.Tab), msg.Type == tea.KeyDown:
+		return m.focusTemplateInput((m.templateFocus + 1) % len(m.templateInputs)), nil
+
+	case key.Matches(msg, keys.ShiftTab), msg.Type == tea.KeyUp:
+		return m.focusTemplateInput((m.templateFocus - 1 + len(m.templateInputs)) % len(m.templateInputs)), nil
+	}
+
+	m.formErr = nil
+	var cmd tea.Cmd
+	m.templateInputs[m.templateFocus], cmd = m.templateInputs[m.templateFocus].Update(msg)
+	return m, cmd
+}
+
+// handleTemplateListKeys handles key presses while choosing a template
+func (m Model) handleTemplateListKeys(msg tea.KeyMsg, keys KeyMap) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.Back):
+		// Back to the form
+		m.viewMode = ViewModeCreate
+		m.formErr = nil
+		return m, nil
+
+	case key.Matches(msg, keys.Up):
+		if m.templateSelection > 0 {
+			m.templateSelection--
+		}
+
+	case key.Matches(msg, keys.Down):
+		if m.templateSelection < len(m.templateNames)-1 {
+			m.templateSelection++
+		}
+
+	case key.Matches(msg, keys.Select):
+		tmpl, err := service.LoadTemplate(config.GetTemplatesDir(), m.templateNames[m.templateSelection])
+		if err != nil {
+			m.formErr = err
+			return m, nil
+		}
+		m.template = tmpl
+		m.formErr = nil
+
+		// Ask for variables, if any
+		m.templateInputs = nil
+		for _, name := range tmpl.Variables() {
+			input := textinput.New()
+			input.Prompt = name + ": "
+			input.Width = 40
+			m.templateInputs = append(m.templateInputs, input)
+		}
+		if len(m.templateInputs) == 0 {
+			return m.createFromTemplate()
+		}
+		return m.focusTemplateInput(0), textinput.Blink
+	}
+	return m, nil
+}
+
+// focusTemplateInput moves the focus to the variable input at index i
+func (m Model) focusTemplateInput(i int) Model {
+	m.templateInputs[m.templateFocus].Blur()
+	m.templateFocus = i
+	m.templateInputs[i].Focus()
+	return m
+}
+
+// createFromTemplate creates the picked template's tasks in the current
+// column, under the parent entered in the form if any
+func (m Model) createFromTemplate() (tea.Model, tea.Cmd) {
+	vars := make(map[string]string)
+	for i, name := range m.template.Variables() {
+		value := strings.TrimSpace(m.templateInputs[i].Value())
+		if value == "" {
+			m.formErr = fmt.Errorf("enter a value for %s", name)
+			return m.focusTemplateInput(i), nil
+		}
+		vars[name] = value
+	}
+
+	parent, subtasks, err := m.template.NewTasks(vars)
+	if err != nil {
+		m.formErr = err
+		return m, nil
+	}
+	parent.Column = m.GetCurrentColumnName()
+	parent.BoardID = m.boardID
+	if ref := strings.TrimSpace(m.formInputs[4].Value()); ref != "" {
+		parentID, err := m.resolveTaskRef(ref)
+		if err != nil {
+			m.formErr = err
+			return m, nil
+		}
+		parent.ParentID = &parentID
+	}
+
+	if err := m.svc.CreateWithSubtasks(parent, subtasks); err != nil {
+		return m.handleSaveError(err)
+	}
+
+	// Make the new task the target of @last
+	saveLast := m.saveState("last task", func(s *config.State) { s.LastTaskID = parent.ID })
+
+	m.statusMessage = fmt.Sprintf("Created task with %d subtasks from template %s", len(subtasks), m.templateNames[m.templateSelection])
+	m.template = nil
+	m.templateInputs = nil
+	m.formInputs = nil
+	m.formErr = nil
+
+	// Go to detail view of the new task
+	savedTask, err := storage.GetTask(m.db, parent.ID)
+	if err != nil {
+		m.viewMode = ViewModeKanban
+		return m, tea.Batch(m.loadTasks, saveLast)
+	}
+	m.detailTask = savedTask
+	m.viewMode = ViewModeDetail
+	return m, tea.Batch(m.loadTasks, saveLast)
+}
+
+// renderTemplatePicker renders the template picker
+func (m Model) renderTemplatePicker() string {
+	var b strings.Builder
+
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(colorGreen).
+		Render("New Task from Template")
+	b.WriteString(title + "\n\n")
+
+	var prompt strings.Builder
+	if m.template == nil {
+		prompt.WriteString("Select a template:\n\n")
+		for i, name := range m.templateNames {
+			if i == m.templateSelection {
+				prompt.WriteString(selectedTaskStyle.Render("> "+name) + "\n")
+			} else {
+				prompt.WriteString(taskStyle.Render("  "+name) + "\n")
+			}
+		}
+		prompt.WriteString("\n")
+		prompt.WriteString(formHelpStyle.Render("enter: select • esc: back to form"))
+	} else {
+		prompt.WriteString(fmt.Sprintf("%s: %s (%d subtasks)\n\n", m.templateNames[m.templateSelection], m.template.Title, len(m.template.Subtasks)))
+		for _, input := range m.templateInputs {
+			prompt.WriteString(input.View() + "\n")
+		}
+		prompt.WriteString("\n")
+		prompt.WriteString(formHelpStyle.Render("tab: next • enter/ctrl+s: create • esc: back"))
+	}
+
+	if m.formErr != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(colorRed).
+			Bold(true)
+		prompt.WriteString("\n\n")
+		prompt.WriteString(errorStyle.Render("Error: " + m.formErr.Error()))
+	}
+
+	b.WriteString(templatePickerStyle.Render(prompt.String()))
+	return b.String()
+}
@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/muesli/termenv"
+)
+
+// Theme is the palette the TUI is drawn with. Colors are named after the
+// Gruvbox palette the TUI was designed with; each one has a role:
+//
+//   - Background: text on green or red fills, like selected buttons
+//   - Selection: background of the selected task
+//   - Text: task titles and values
+//   - Gray: borders, labels, help and P5
+//   - Red: delete dialogs, errors and P1
+//   - Orange: P2
+//   - Yellow: in-progress header, warnings and P3
+//   - Green: titles, active borders, progress and P4
+//   - Aqua: inbox header, tags and marked tasks
+type Theme struct {
+	Name       string
+	Background lipgloss.TerminalColor
+	Selection  lipgloss.TerminalColor
+	Text       lipgloss.TerminalColor
+	Gray       lipgloss.TerminalColor
+	Red        lipgloss.TerminalColor
+	Orange     lipgloss.TerminalColor
+	Yellow     lipgloss.TerminalColor
+	Green      lipgloss.TerminalColor
+	Aqua       lipgloss.TerminalColor
+}
+
+// Themes picked by ui.theme = "auto"
+const (
+	defaultDarkTheme  = "gruvbox-dark"
+	defaultLightTheme = "gruvbox-light"
+	noColorTheme      = "monochrome"
+)
+
+// builtinThemes are the themes ui.theme can name without a theme file
+var builtinThemes = map[string]Theme{
+	"gruvbox-dark": palette("gruvbox-dark",
+		"#282828", "#504945", "#ebdbb2", "#928374", "#fb4934", "#fe8019", "#fabd2f", "#b8bb26", "#8ec07c"),
+	"gruvbox-light": palette("gruvbox-light",
+		"#fbf1c7", "#d5c4a1", "#3c3836", "#928374", "#9d0006", "#af3a03", "#b57614", "#79740e", "#427b58"),
+	"solarized-dark": palette("solarized-dark",
+		"#002b36", "#073642", "#93a1a1", "#657b83", "#dc322f", "#cb4b16", "#b58900", "#859900", "#2aa198"),
+	"solarized-light": palette("solarized-light",
+		"#fdf6e3", "#eee8d5", "#586e75", "#93a1a1", "#dc322f", "#cb4b16", "#b58900", "#859900", "#2aa198"),
+	"nord": palette("nord",
+		"#2e3440", "#434c5e", "#eceff4", "#7b88a1", "#bf616a", "#d08770", "#ebcb8b", "#a3be8c", "#88c0d0"),
+	// The terminal's own bright ANSI colors, on black
+	"high-contrast": palette("high-contrast",
+		"0", "4", "15", "7", "9", "13", "11", "10", "14"),
+	// No colors at all, for NO_COLOR; selections use reverse video
+	"monochrome": {
+		Name:       "monochrome",
+		Background: lipgloss.NoColor{},
+		Selection:  lipgloss.NoColor{},
+		Text:       lipgloss.NoColor{},
+		Gray:       lipgloss.NoColor{},
+		Red:        lipgloss.NoColor{},
+		Orange:     lipgloss.NoColor{},
+		Yellow:     lipgloss.NoColor{},
+		Green:      lipgloss.NoColor{},
+		Aqua:       lipgloss.NoColor{},
+	},
+}
+
+func palette(name string, colors ...string) Theme {
+	return Theme{
+		Name:       name,
+		Background: lipgloss.Color(colors[0]),
+		Selection:  lipgloss.Color(colors[1]),
+		Text:       lipgloss.Color(colors[2]),
+		Gray:       lipgloss.Color(colors[3]),
+		Red:        lipgloss.Color(colors[4]),
+		Orange:     lipgloss.Color(colors[5]),
+		Yellow:     lipgloss.Color(colors[6]),
+		Green:      lipgloss.Color(colors[7]),
+		Aqua:       lipgloss.Color(colors[8]),
+	}
+}
+
+// BuiltinThemes returns the names of the built-in themes
+func BuiltinThemes() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// themeFile is a user-defined theme, stored as <name>.toml in the themes
+// directory. It starts from a base theme and replaces the colors it sets.
+type themeFile struct {
+	Base       string `toml:"base"` // Built-in theme; empty picks one like "auto"
+	Background string `toml:"background"`
+	Selection  string `toml:"selection"`
+	Text       string `toml:"text"`
+	Gray       string `toml:"gray"`
+	Red        string `toml:"red"`
+	Orange     string `toml:"orange"`
+	Yellow     string `toml:"yellow"`
+	Green      string `toml:"green"`
+	Aqua       string `toml:"aqua"`
+}
+
+// themeColor matches the colors a theme file may use: #rgb or #rrggbb hex,
+// or an ANSI color number from 0 to 255
+var themeColor = regexp.MustCompile(`^(#[0-9A-Fa-f]{3}|#[0-9A-Fa-f]{6}|[0-9]|[1-9][0-9]|1[0-9][0-9]|2[0-4][0-9]|25[0-5])$`)
+
+// LoadTheme returns the theme named by the ui.theme setting: "auto", a
+// built-in theme or a file in the themes directory. When NO_COLOR is set the
+// monochrome theme is always used.
+func LoadTheme(name string) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return builtinThemes[noColorTheme], nil
+	}
+	if name == "" || name == "auto" {
+		return autoTheme(), nil
+	}
+	if theme, ok := builtinThemes[name]; ok {
+		return theme, nil
+	}
+
+	path := filepath.Join(config.GetThemesDir(), name+".toml")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return autoTheme(), fmt.Errorf("unknown theme %q (want auto, one of %s, or a file in %s)",
+			name, strings.Join(BuiltinThemes(), ", "), config.GetThemesDir())
+	}
+	if err != nil {
+		return autoTheme(), fmt.Errorf("failed to read theme: %w", err)
+	}
+	theme, err := parseTheme(name, data)
+	if err != nil {
+		return autoTheme(), fmt.Errorf("%s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// parseTheme parses and validates a theme file
+func parseTheme(name string, data []byte) (Theme, error) {
+	var file themeFile
+	meta, err := toml.Decode(string(data), &file)
+	if err != nil {
+		return Theme{}, fmt.Errorf("invalid theme: %w", err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return Theme{}, fmt.Errorf("unknown field '%s'", undecoded[0])
+	}
+
+	var theme Theme
+	if file.Base == "" {
+		theme = autoTheme()
+	} else {
+		base, ok := builtinThemes[file.Base]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown base theme %q (want one of %s)", file.Base, strings.Join(BuiltinThemes(), ", "))
+		}
+		theme = base
+	}
+	theme.Name = name
+
+	colors := []struct {
+		key   string
+		value string
+		color *lipgloss.TerminalColor
+	}{
+		{"background", file.Background, &theme.Background},
+		{"selection", file.Selection, &theme.Selection},
+		{"text", file.Text, &theme.Text},
+		{"gray", file.Gray, &theme.Gray},
+		{"red", file.Red, &theme.Red},
+		{"orange", file.Orange, &theme.Orange},
+		{"yellow", file.Yellow, &theme.Yellow},
+		{"green", file.Green, &theme.Green},
+		{"aqua", file.Aqua, &theme.Aqua},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		if !themeColor.MatchString(c.value) {
+			return Theme{}, fmt.Errorf("invalid color %q for %s (want #rrggbb or an ANSI color from 0 to 255)", c.value, c.key)
+		}
+		*c.color = lipgloss.Color(c.value)
+	}
+	return theme, nil
+}
+
+// autoTheme picks the default theme for the terminal's background
+func autoTheme() Theme {
+	if termenv.HasDarkBackground() {
+		return builtinThemes[defaultDarkTheme]
+	}
+	return builtinThemes[defaultLightTheme]
+}
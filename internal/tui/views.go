@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/lucasefe/ontop/internal/config"
+	"github.com/lucasefe/ontop/internal/models"
+	"github.com/lucasefe/ontop/internal/service"
+)
+
+// viewState is how the board was shown before a saved view was applied
+type viewState struct {
+	filterExpr string
+	sortMode   SortMode
+	layout     ViewLayout
+}
+
+// nextView applies the saved view after the one in use, in name order. After
+// the last one it goes back to the filter, sort and layout in use before the
+// first. Views are read each time, so views saved from the CLI show up
+// without restarting.
+func (m Model) nextView() (tea.Model, tea.Cmd) {
+	views, err := service.NewViewService(m.db).List()
+	if err != nil {
+		m.err = err
+		return m, tea.Quit
+	}
+	if len(views) == 0 && m.view == nil {
+		m.statusMessage = "No saved views: type a filter with f and save it with " + m.keys.Save.Help().Key
+		return m, nil
+	}
+
+	next := 0
+	if m.view != nil {
+		current := slices.IndexFunc(views, func(v *models.View) bool { return strings.EqualFold(v.Name, m.view.Name) })
+		next = current + 1 // A deleted view starts over from the first
+	}
+	if next >= len(views) {
+		m.leaveView()
+		m.statusMessage = "Left saved views"
+		return m, nil
+	}
+	return m.useView(views[next]), nil
+}
+
+// useView switches to view, remembering how the board was shown before the
+// first one
+func (m Model) useView(view *models.View) Model {
+	if m.view == nil {
+		m.viewBefore = viewState{filterExpr: m.filterExpr, sortMode: m.sortMode, layout: m.viewLayout}
+	}
+	if err := m.applyView(view); err != nil {
+		m.statusMessage = fmt.Sprintf("View %s: %v", view.Name, err)
+		return m
+	}
+	m.statusMessage = ""
+	return m
+}
+
+// applyView filters the board by view and switches to its sort and layout,
+// when it has them. An invalid filter leaves the board as it was.
+func (m *Model) applyView(view *models.View) error {
+	if err := m.setFilter(view.Filter); err != nil {
+		return err
+	}
+	selectedID := m.selectedID()
+	m.view = view
+	if i := slices.Index(config.SortModes, view.Sort); i >= 0 {
+		m.sortMode = SortMode(i)
+	}
+	switch view.Layout {
+	case "column":
+		m.setLayout(LayoutColumn)
+	case "row":
+		m.setLayout(LayoutRow)
+	}
+	m.restoreSelection(selectedID)
+	return nil
+}
+
+// leaveView stops using the saved view, restoring the filter, sort and
+// layout from before it
+func (m *Model) leaveView() {
+	m.view = nil
+	_ = m.setFilter(m.viewBefore.filterExpr) // Was valid when applied
+	selectedID := m.selectedID()
+	m.sortMode = m.viewBefore.sortMode
+	m.setLayout(m.viewBefore.layout)
+	m.restoreSelection(selectedID)
+}
+
+// saveView saves the current filter, sort and layout as the view name and
+// makes it the one in use
+func (m Model) saveView(name string) (Model, error) {
+	view, err := service.NewViewService(m.db).Save(&models.View{
+		Name:   name,
+		Filter: m.filterExpr,
+		Sort:   config.SortModes[m.sortMode],
+		Layout: layoutName(m.viewLayout),
+	})
+	if err != nil {
+		return m, err
+	}
+	if m.view == nil {
+		m.viewBefore = viewState{filterExpr: m.filterBefore, sortMode: m.sortMode, layout: m.viewLayout}
+	}
+	m.view = view
+	return m, nil
+}
+
+// viewStatus returns the status bar segment naming the saved view in use, or
+// "" if there is none
+func (m Model) viewStatus() string {
+	if m.view == nil {
+		return ""
+	}
+	return "  •  Saved view: " + m.view.Name
+}
+
+// setLayout switches the board layout, resetting the scroll offsets, which
+// don't carry over between layouts
+func (m *Model) setLayout(layout ViewLayout) {
+	if layout == m.viewLayout {
+		return
+	}
+	m.viewLayout = layout
+	m.rowScrollOffset = make(map[int]int)
+	m.columnScrollOffset = make(map[int]int)
+}
+
+// selectedID returns the ID of the selected task, or "" if there is none
+func (m *Model) selectedID() string {
+	if task := m.GetSelectedTask(); task != nil {
+		return task.ID
+	}
+	return ""
+}
+
+// layoutName returns the config name of a layout, as used by ui.view_mode
+// and saved views
+func layoutName(layout ViewLayout) string {
+	if layout == LayoutRow {
+		return "row"
+	}
+	return "column"
+}